@@ -0,0 +1,26 @@
+package pipeline
+
+import "context"
+
+// FromFunc2 adapts a handler of the form func(ctx, x) (x, error) into a StepFunc that
+// calls it with context.Background(). To pass the execution's context through instead,
+// use the handler as a StepFuncContext with ThenContext.
+func FromFunc2[T any](f func(context.Context, T) (T, error)) StepFunc[T] {
+	return func(input T) (T, error) {
+		return f(context.Background(), input)
+	}
+}
+
+// ToFunc2 adapts a StepFunc into a handler of the form func(ctx, x) (x, error) that
+// ignores its context, for APIs that expect that signature.
+func ToFunc2[T any](step StepFunc[T]) func(context.Context, T) (T, error) {
+	return withContext(step)
+}
+
+// FromValidator adapts a validation function into a StepFunc that passes its input
+// through unchanged, failing with validate's error if it reports one.
+func FromValidator[T any](validate func(T) error) StepFunc[T] {
+	return func(input T) (T, error) {
+		return input, validate(input)
+	}
+}
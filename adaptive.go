@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+)
+
+// aimdLimiter is a concurrency limit adjusted by additive increase, multiplicative
+// decrease: it grows by one after every fast success and halves after an error or a
+// call much slower than the moving average.
+type aimdLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	max      int
+	inflight int
+	ewma     float64 // moving average latency in nanoseconds
+	onLimit  func(int)
+}
+
+// Tuning for aimdLimiter: the weight of a new sample in the moving average, and how
+// many times slower than average a call must be to count as a latency spike.
+const (
+	aimdAlpha      = 0.2
+	aimdSlowFactor = 2
+)
+
+func (l *aimdLimiter) acquire() {
+	l.mu.Lock()
+	for l.inflight >= l.limit {
+		l.cond.Wait()
+	}
+	l.inflight++
+	l.mu.Unlock()
+}
+
+func (l *aimdLimiter) release(latency time.Duration, err error) {
+	l.mu.Lock()
+	l.inflight--
+	prev := l.limit
+	ns := float64(latency)
+	if err != nil || (l.ewma > 0 && ns > aimdSlowFactor*l.ewma) {
+		l.limit = max(1, l.limit/2)
+	} else if l.limit < l.max {
+		l.limit++
+	}
+	if l.ewma == 0 {
+		l.ewma = ns
+	} else {
+		l.ewma += aimdAlpha * (ns - l.ewma)
+	}
+	limit := l.limit
+	l.cond.Broadcast()
+	l.mu.Unlock()
+	if limit != prev && l.onLimit != nil {
+		l.onLimit(limit)
+	}
+}
+
+// AdaptiveParallel runs steps on the same input like Parallel, but with a concurrency
+// limit that adapts to how the steps behave, to protect backends of varying capacity.
+// The limit starts at one and grows by one after each fast success, up to the number of
+// steps; it halves when a step fails or runs more than twice as slow as the moving
+// average. The limit is shared by all calls of the returned step, so it persists across
+// executions. onLimit, if not nil, is called with each new limit and may be called
+// concurrently.
+func AdaptiveParallel[T any](combiner func([]T) (T, error), onLimit func(int), steps ...StepFunc[T]) StepFunc[T] {
+	l := &aimdLimiter{limit: 1, max: max(1, len(steps)), onLimit: onLimit}
+	l.cond = sync.NewCond(&l.mu)
+	return func(input T) (T, error) {
+		var (
+			wg      sync.WaitGroup
+			results = make([]T, len(steps))
+			errs    = make([]error, len(steps))
+		)
+		wg.Add(len(steps))
+		for i, step := range steps {
+			l.acquire()
+			go func(idx int, s StepFunc[T]) {
+				defer wg.Done()
+				start := time.Now()
+				results[idx], errs[idx] = s(input)
+				l.release(time.Since(start), errs[idx])
+			}(i, step)
+		}
+		wg.Wait()
+		return combine(input, combiner, results, errs)
+	}
+}
@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"fmt"
+	"runtime/metrics"
+)
+
+// heapAllocs is the runtime metric counting bytes allocated on the heap since start.
+const heapAllocs = "/gc/heap/allocs:bytes"
+
+// AllocGuard creates a Middleware that fails a step with ErrTooLarge, returning the
+// input unchanged, when the heap grew by more than maxBytes of allocations while it ran.
+// It is best-effort and coarse: the count is process-wide, so allocations by concurrent
+// goroutines are attributed to the step, the runtime only updates it periodically, and
+// the step is not stopped early, only its output discarded. Reading the metric is
+// cheap next to runtime.ReadMemStats, but not free; enable it only for suspicious
+// inputs with When.
+func AllocGuard[T any](maxBytes uint64) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			sample := []metrics.Sample{{Name: heapAllocs}}
+			metrics.Read(sample)
+			before := sample[0].Value.Uint64()
+			out, err := next(input)
+			metrics.Read(sample)
+			if n := sample[0].Value.Uint64() - before; n > maxBytes {
+				return input, fmt.Errorf("%w: step allocated %d bytes, over %d", ErrTooLarge, n, maxBytes)
+			}
+			return out, err
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// ExecuteBatchParallel runs the pipeline over inputs using up to workers concurrent
+// executions, returning outputs and errors at the same indices as their inputs.
+// When ctx is cancelled, in-flight executions see the cancellation through
+// ExecuteContext and items not yet started report ctx.Err(); items that already
+// completed keep their real results. A workers value below 1 runs one worker.
+func (p *Pipeline[T]) ExecuteBatchParallel(ctx context.Context, inputs []T, workers int) ([]T, []error) {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]T, len(inputs))
+	errs := make([]error, len(inputs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i], errs[i] = p.ExecuteContext(ctx, inputs[i])
+			}
+		}()
+	}
+	next := 0
+feed:
+	for ; next < len(inputs); next++ {
+		select {
+		case indices <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+	for i := next; i < len(inputs); i++ {
+		results[i], errs[i] = inputs[i], ctx.Err()
+	}
+	return results, errs
+}
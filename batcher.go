@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// batchCall is one caller waiting on a Batcher.
+type batchCall[T any] struct {
+	input T
+	done  chan Result[T]
+}
+
+// batcher groups concurrent calls into batches. gen identifies the batch being
+// collected, so a timer left over from a batch already flushed by size does nothing.
+type batcher[T any] struct {
+	mu       sync.Mutex
+	maxBatch int
+	maxWait  time.Duration
+	flush    func([]T) ([]T, error)
+	pending  []batchCall[T]
+	gen      uint64
+}
+
+// Batcher creates a StepFunc that groups calls made concurrently, for example by
+// separate executions, into batches and calls flush once per batch, returning each
+// caller its own result: the DataLoader pattern for cutting per-item round trips. A
+// batch is flushed once it holds maxBatch inputs or maxWait after its first input
+// arrived, whichever comes first, so a call waits at most about maxWait. flush must
+// return one output per input, in order; if it fails, every caller in the batch gets
+// its error along with its own input, and if it returns the wrong number of outputs,
+// they get ErrBatchMismatch.
+func Batcher[T any](maxBatch int, maxWait time.Duration, flush func([]T) ([]T, error)) StepFunc[T] {
+	b := &batcher[T]{maxBatch: max(1, maxBatch), maxWait: maxWait, flush: flush}
+	return func(input T) (T, error) {
+		call := batchCall[T]{input: input, done: make(chan Result[T], 1)}
+		b.mu.Lock()
+		b.pending = append(b.pending, call)
+		switch len(b.pending) {
+		case b.maxBatch:
+			batch := b.cut()
+			b.mu.Unlock()
+			go b.run(batch)
+		case 1:
+			gen := b.gen
+			b.mu.Unlock()
+			time.AfterFunc(b.maxWait, func() { b.flushGen(gen) })
+		default:
+			b.mu.Unlock()
+		}
+		r := <-call.done
+		return r.Value, r.Err
+	}
+}
+
+// cut takes the pending batch and starts a new one. b.mu must be held.
+func (b *batcher[T]) cut() []batchCall[T] {
+	batch := b.pending
+	b.pending = nil
+	b.gen++
+	return batch
+}
+
+// flushGen flushes the pending batch if it is still batch gen.
+func (b *batcher[T]) flushGen(gen uint64) {
+	b.mu.Lock()
+	if b.gen != gen {
+		b.mu.Unlock()
+		return
+	}
+	batch := b.cut()
+	b.mu.Unlock()
+	b.run(batch)
+}
+
+// run flushes batch and delivers each result to its caller.
+func (b *batcher[T]) run(batch []batchCall[T]) {
+	inputs := make([]T, len(batch))
+	for i, c := range batch {
+		inputs[i] = c.input
+	}
+	outputs, err := b.flush(inputs)
+	if err == nil && len(outputs) != len(inputs) {
+		err = fmt.Errorf("%w: %d results for %d inputs", ErrBatchMismatch, len(outputs), len(inputs))
+	}
+	for i, c := range batch {
+		if err != nil {
+			c.done <- Result[T]{Value: c.input, Err: err}
+			continue
+		}
+		c.done <- Result[T]{Value: outputs[i]}
+	}
+}
@@ -0,0 +1,131 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState string
+
+// A breaker is closed while calls go through, open while it rejects them, and half-open
+// while it lets a single trial call through after the reset timeout.
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// BreakerState is a snapshot of a CircuitBreaker: its state, its count of consecutive
+// failures, and while open, the time left until it lets a trial call through.
+type BreakerState struct {
+	State    CircuitState
+	Failures int
+	ResetIn  time.Duration
+}
+
+// CircuitBreaker stops calling a failing step: after threshold consecutive failures it
+// opens and rejects calls with ErrCircuitOpen until reset has passed, then lets one
+// trial call through, closing again if it succeeds. It is safe for concurrent use, and
+// its state lives outside any pipeline, so it survives recompilation and can be shared.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	reset     time.Duration
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	trial     bool // a half-open trial call is in flight
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker that opens after threshold (at
+// least one) consecutive failures and tries again after reset.
+func NewCircuitBreaker(threshold int, reset time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: max(1, threshold), reset: reset, state: CircuitClosed}
+}
+
+// allow reports whether a call may proceed, moving an open breaker to half-open once
+// its reset timeout has passed.
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.reset {
+		b.state = CircuitHalfOpen
+	}
+	switch b.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+	}
+	return true
+}
+
+// done records the outcome of a call that allow let through.
+func (b *CircuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if err == nil {
+		b.state, b.failures = CircuitClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = CircuitOpen, time.Now()
+	}
+}
+
+// State returns a snapshot of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := BreakerState{State: b.state, Failures: b.failures}
+	if b.state == CircuitOpen {
+		s.ResetIn = max(0, b.reset-time.Since(b.openedAt))
+	}
+	return s
+}
+
+// Breaker creates a Middleware that guards steps with b, failing calls it rejects with
+// ErrCircuitOpen and the input unchanged. A call that panics counts as a failure before
+// the panic continues.
+func Breaker[T any](b *CircuitBreaker) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (out T, err error) {
+			if !b.allow() {
+				return input, ErrCircuitOpen
+			}
+			defer func() {
+				if v := recover(); v != nil {
+					b.done(PanicError{Value: v})
+					panic(v)
+				}
+				b.done(err)
+			}()
+			return next(input)
+		}
+	}
+}
+
+// UseBreaker appends Breaker(b) as a middleware named name and registers b for Health.
+func (p *Pipeline[T]) UseBreaker(name string, b *CircuitBreaker) *Pipeline[T] {
+	return p.addMiddleware(middleware[T]{name: name, plain: Breaker[T](b), breaker: b})
+}
+
+// Health returns the state of every circuit breaker installed with UseBreaker, keyed by
+// middleware name, for reporting an open breaker as degraded in a health check.
+func (p *Pipeline[T]) Health() map[string]BreakerState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	health := make(map[string]BreakerState)
+	for _, mw := range p.middlewares {
+		if mw.breaker != nil {
+			health[mw.name] = mw.breaker.State()
+		}
+	}
+	return health
+}
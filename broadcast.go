@@ -0,0 +1,40 @@
+package pipeline
+
+import "sync"
+
+// Broadcast returns a function that runs the same input through several complete
+// pipelines concurrently and returns their outputs and errors in the order the
+// pipelines were given. Unlike Parallel, each branch is a full pipeline with its own
+// steps and middleware, which suits dispatching one event to independent chains.
+func Broadcast[T any](pipelines ...*Pipeline[T]) func(T) ([]T, []error) {
+	return BroadcastLimit(0, pipelines...)
+}
+
+// BroadcastLimit is like Broadcast but runs at most limit pipelines at a time.
+// A limit of zero or less runs them all at once.
+func BroadcastLimit[T any](limit int, pipelines ...*Pipeline[T]) func(T) ([]T, []error) {
+	if limit <= 0 || limit > len(pipelines) {
+		limit = len(pipelines)
+	}
+	return func(input T) ([]T, []error) {
+		var (
+			wg      sync.WaitGroup
+			sem     = make(chan struct{}, limit)
+			results = make([]T, len(pipelines))
+			errs    = make([]error, len(pipelines))
+		)
+		wg.Add(len(pipelines))
+		for i, p := range pipelines {
+			sem <- struct{}{}
+			go func(idx int, p *Pipeline[T]) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				results[idx], errs[idx] = p.Execute(input)
+			}(i, p)
+		}
+		wg.Wait()
+		return results, errs
+	}
+}
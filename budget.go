@@ -0,0 +1,42 @@
+package pipeline
+
+import "maps"
+
+// WithBudget caps the total cost of one execution, as reported by steps added with
+// ThenWithCost. A max of zero or less disables the cap.
+func WithBudget[T any](max int) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.budget = max
+	}
+}
+
+// ThenWithCost appends a StepFunc whose cost for a given input is reported by cost,
+// applying any registered Middleware. Costs accumulate across the steps of one
+// execution; a step that would overrun the pipeline's budget is not run.
+func (p *Pipeline[T]) ThenWithCost(cost func(T) int, step StepFunc[T]) *Pipeline[T] {
+	return p.add(stage[T]{cost: cost}, step, nil)
+}
+
+// WithBudgetSplit divides the time left on an execution's context deadline across the
+// steps still to run, so an early slow step cannot starve the later ones. Before each
+// step, the time left is split in proportion to the weights of the remaining steps,
+// and the step runs with a context whose deadline is its share. Weights come from
+// hints, keyed by step name; steps without a positive hint weigh one. Executions
+// without a deadline are unaffected. Only context-aware steps and middlewares see the
+// derived deadline.
+func WithBudgetSplit[T any](hints map[string]float64) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.budgetSplit = maps.Clone(hints)
+		if p.budgetSplit == nil {
+			p.budgetSplit = make(map[string]float64)
+		}
+	}
+}
+
+// budgetWeight returns the weight of s for WithBudgetSplit.
+func (p *Pipeline[T]) budgetWeight(s stage[T]) float64 {
+	if w := p.budgetSplit[s.name]; w > 0 {
+		return w
+	}
+	return 1
+}
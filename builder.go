@@ -0,0 +1,56 @@
+package pipeline
+
+import "fmt"
+
+// StepSpec declares one step of a pipeline built by a Builder: the name of a registered
+// step factory and the parameters passed to it. Its field tags let specs be decoded
+// from configuration files.
+type StepSpec struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// StepFactory creates a step from the parameters given in a StepSpec, returning an
+// error if they are invalid.
+type StepFactory[T any] func(params map[string]any) (StepFunc[T], error)
+
+// Builder constructs pipelines from declarative specs using a registry of named step
+// factories, so pipeline composition can be configured without recompiling. Register
+// every factory before building; RegisterStep must not be called concurrently with
+// BuildFromSpec.
+type Builder[T any] struct {
+	factories map[string]StepFactory[T]
+	opts      []Option[T]
+}
+
+// NewBuilder creates a Builder whose pipelines are created with opts.
+func NewBuilder[T any](opts ...Option[T]) *Builder[T] {
+	return &Builder[T]{factories: make(map[string]StepFactory[T]), opts: opts}
+}
+
+// RegisterStep registers factory under name, replacing any factory already registered
+// under it.
+func (b *Builder[T]) RegisterStep(name string, factory StepFactory[T]) *Builder[T] {
+	b.factories[name] = factory
+	return b
+}
+
+// BuildFromSpec creates a pipeline with one step per entry of spec, in order, each
+// named after its factory. It fails with ErrUnknownStep if an entry names an
+// unregistered factory, or with the factory's error, prefixed by the entry's index and
+// name, if a factory rejects its parameters.
+func (b *Builder[T]) BuildFromSpec(spec []StepSpec) (*Pipeline[T], error) {
+	p := New(b.opts...)
+	for i, s := range spec {
+		factory, ok := b.factories[s.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q at step %d", ErrUnknownStep, s.Name, i)
+		}
+		step, err := factory(s.Params)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i, s.Name, err)
+		}
+		p.ThenNamed(s.Name, step)
+	}
+	return p, nil
+}
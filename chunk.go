@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Chunk creates a StepFunc that splits its input into elements, runs process on each
+// element and reassembles the processed elements with join. Elements are processed
+// chunkSize at a time: the elements of one chunk run concurrently and chunks run one
+// after another, which bounds memory and goroutines for large inputs. A chunkSize of
+// one or less processes elements sequentially. On the first chunk with a failure,
+// processing stops and the input is returned with the failure of the lowest-index
+// element.
+func Chunk[T, E any](split func(T) []E, process StepFunc[E], join func(T, []E) T, chunkSize int) StepFunc[T] {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	return func(input T) (T, error) {
+		elems := split(input)
+		out := make([]E, len(elems))
+		errs := make([]error, chunkSize)
+		for start := 0; start < len(elems); start += chunkSize {
+			end := min(start+chunkSize, len(elems))
+			var wg sync.WaitGroup
+			wg.Add(end - start)
+			for i := start; i < end; i++ {
+				go func(i int) {
+					defer wg.Done()
+					out[i], errs[i-start] = process(elems[i])
+				}(i)
+			}
+			wg.Wait()
+			for _, err := range errs[:end-start] {
+				if err != nil {
+					return input, err
+				}
+			}
+		}
+		return join(input, out), nil
+	}
+}
+
+// MapSlice creates a StepFunc for pipelines over slices that runs apply on each element
+// in order, producing a new slice of the same length and order. Every element is
+// processed even when some fail; if any do, the input is returned with the failures
+// joined, each prefixed by its element's index. Use MapSliceFailFast to stop at the
+// first failure instead.
+func MapSlice[E any](apply func(E) (E, error)) StepFunc[[]E] {
+	return func(input []E) ([]E, error) {
+		out := make([]E, len(input))
+		var failed []error
+		for i, e := range input {
+			v, err := apply(e)
+			if err != nil {
+				failed = append(failed, fmt.Errorf("element %d: %w", i, err))
+				continue
+			}
+			out[i] = v
+		}
+		if len(failed) > 0 {
+			return input, errors.Join(failed...)
+		}
+		return out, nil
+	}
+}
+
+// MapSliceFailFast behaves like MapSlice but stops at the first failing element,
+// returning the input with that element's error prefixed by its index.
+func MapSliceFailFast[E any](apply func(E) (E, error)) StepFunc[[]E] {
+	return func(input []E) ([]E, error) {
+		out := make([]E, len(input))
+		for i, e := range input {
+			v, err := apply(e)
+			if err != nil {
+				return input, fmt.Errorf("element %d: %w", i, err)
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,131 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// AnyPipeline is a type-erased pipeline, for dynamically-typed inputs such as plugin
+// payloads. Use Coerce to bring its values into a strongly-typed sub-pipeline.
+type AnyPipeline = Pipeline[any]
+
+// Coerce creates a function converting an any into T, for bridging an AnyPipeline into
+// a Pipeline[T]. It applies these rules in order:
+//
+//   - a value that already is a T, or implements it if T is an interface, is returned
+//     as is;
+//   - a number, including a json.Number, converts to any numeric T as long as the value
+//     is preserved: floats convert to integers only if they are whole, and negative
+//     values never convert to unsigned types;
+//   - anything else is round-tripped through encoding/json, so a map[string]any fills
+//     a struct and a []any fills a typed slice, following encoding/json's usual rules.
+//
+// A value that matches none of them, including nil, fails with ErrTypeMismatch.
+func Coerce[T any]() func(any) (T, error) {
+	target := reflect.TypeFor[T]()
+	return func(v any) (T, error) {
+		var out T
+		if t, ok := v.(T); ok {
+			return t, nil
+		}
+		if v == nil {
+			return out, fmt.Errorf("%w: cannot coerce nil to %v", ErrTypeMismatch, target)
+		}
+		if n, ok, err := coerceNumber(v, target); ok {
+			if err != nil {
+				return out, err
+			}
+			return n.Interface().(T), nil
+		}
+		data, err := json.Marshal(v)
+		if err == nil {
+			if err = json.Unmarshal(data, &out); err == nil {
+				return out, nil
+			}
+		}
+		return out, fmt.Errorf("%w: cannot coerce %T to %v: %v", ErrTypeMismatch, v, target, err)
+	}
+}
+
+// coerceNumber converts v to target when both are numeric. It reports false if either
+// is not, and an error if the value cannot be represented exactly.
+func coerceNumber(v any, target reflect.Type) (reflect.Value, bool, error) {
+	if num, ok := v.(json.Number); ok {
+		if i, err := num.Int64(); err == nil {
+			v = i
+		} else if f, err := num.Float64(); err == nil {
+			v = f
+		} else {
+			return reflect.Value{}, false, nil
+		}
+	}
+	src := reflect.ValueOf(v)
+	if !isNumeric(src.Kind()) || !isNumeric(target.Kind()) {
+		return reflect.Value{}, false, nil
+	}
+	out := reflect.New(target).Elem()
+	lost := fmt.Errorf("%w: %v does not fit in %v", ErrTypeMismatch, v, target)
+	switch {
+	case out.CanInt():
+		var i int64
+		switch {
+		case src.CanInt():
+			i = src.Int()
+		case src.CanUint():
+			u := src.Uint()
+			if i = int64(u); i < 0 {
+				return out, true, lost
+			}
+		default:
+			f := src.Float()
+			if i = int64(f); float64(i) != f {
+				return out, true, lost
+			}
+		}
+		if out.OverflowInt(i) {
+			return out, true, lost
+		}
+		out.SetInt(i)
+	case out.CanUint():
+		var u uint64
+		switch {
+		case src.CanInt():
+			i := src.Int()
+			if i < 0 {
+				return out, true, lost
+			}
+			u = uint64(i)
+		case src.CanUint():
+			u = src.Uint()
+		default:
+			f := src.Float()
+			if u = uint64(f); f < 0 || float64(u) != f {
+				return out, true, lost
+			}
+		}
+		if out.OverflowUint(u) {
+			return out, true, lost
+		}
+		out.SetUint(u)
+	default:
+		var f float64
+		switch {
+		case src.CanInt():
+			f = float64(src.Int())
+		case src.CanUint():
+			f = float64(src.Uint())
+		default:
+			f = src.Float()
+		}
+		if out.OverflowFloat(f) {
+			return out, true, lost
+		}
+		out.SetFloat(f)
+	}
+	return out, true, nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Collector is a mutex-guarded map that the branches of ParallelCollect write side
+// outputs to and its combiner reads. It is safe for concurrent use.
+type Collector[K comparable, V any] struct {
+	mu     sync.Mutex
+	values map[K]V
+}
+
+// Set stores v under k, replacing any previous value.
+func (c *Collector[K, V]) Set(k K, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[K]V)
+	}
+	c.values[k] = v
+}
+
+// Get returns the value stored under k and whether there is one.
+func (c *Collector[K, V]) Get(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[k]
+	return v, ok
+}
+
+// Values returns a copy of every stored value.
+func (c *Collector[K, V]) Values() map[K]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.values)
+}
+
+// CollectStep is a ParallelCollect branch: a context-aware step that may also report
+// side outputs through the collector.
+type CollectStep[T any, K comparable, V any] func(ctx context.Context, input T, c *Collector[K, V]) (T, error)
+
+// ParallelCollect behaves like ParallelContext, but gives every branch a Collector,
+// fresh for each call, to report structured side outputs of any shape through, and
+// passes it to combiner along with the branch outputs. A nil combiner returns the input
+// once every branch has succeeded.
+func ParallelCollect[T any, K comparable, V any](combiner func(results []T, c *Collector[K, V]) (T, error), steps ...CollectStep[T, K, V]) StepFuncContext[T] {
+	return func(ctx context.Context, input T) (T, error) {
+		c := new(Collector[K, V])
+		branches := make([]StepFuncContext[T], len(steps))
+		for i, s := range steps {
+			branches[i] = func(ctx context.Context, input T) (T, error) {
+				return s(ctx, input, c)
+			}
+		}
+		var combine func([]T) (T, error)
+		if combiner != nil {
+			combine = func(results []T) (T, error) {
+				return combiner(results, c)
+			}
+		}
+		return ParallelContext(combine, branches...)(ctx, input)
+	}
+}
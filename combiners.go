@@ -0,0 +1,49 @@
+package pipeline
+
+// Combiner merges the outputs of parallel steps into a single value.
+type Combiner[T any] func([]T) (T, error)
+
+// Number is the set of numeric types supported by SumCombiner.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumCombiner adds up all results. It can be passed to Parallel directly, e.g.
+// Parallel(SumCombiner[int], steps...).
+func SumCombiner[T Number](results []T) (T, error) {
+	var sum T
+	for _, r := range results {
+		sum += r
+	}
+	return sum, nil
+}
+
+// FirstNonZeroCombiner returns the first result that is not the zero value of T,
+// or the zero value if every result is zero.
+func FirstNonZeroCombiner[T comparable](results []T) (T, error) {
+	var zero T
+	for _, r := range results {
+		if r != zero {
+			return r, nil
+		}
+	}
+	return zero, nil
+}
+
+// MergeCombiner returns a Combiner that folds the results pairwise from left to right
+// with merge. No results yield the zero value of T.
+func MergeCombiner[T any](merge func(a, b T) T) Combiner[T] {
+	return func(results []T) (T, error) {
+		var acc T
+		for i, r := range results {
+			if i == 0 {
+				acc = r
+				continue
+			}
+			acc = merge(acc, r)
+		}
+		return acc, nil
+	}
+}
@@ -0,0 +1,22 @@
+package pipeline
+
+// AsStep returns the pipeline as a context-aware step, so it can be embedded in another
+// pipeline of the same type. Each call runs the whole pipeline, with its own
+// middlewares, pre-steps, post-steps and finalizers, through ExecuteContext.
+func (p *Pipeline[T]) AsStep() StepFuncContext[T] {
+	return p.ExecuteContext
+}
+
+// ThenPipeline appends sub as a single step, with applyOuter deciding whether this
+// pipeline's middlewares wrap it. sub's own middlewares always wrap each of its steps,
+// inside any outer ones. For outer middlewares O1 and O2 registered in that order, and
+// sub's middlewares I1 and I2 around its steps s1 and s2, a call nests as
+//
+//	applyOuter:  O1(O2( sub[ I1(I2(s1)) -> I1(I2(s2)) ] ))
+//	otherwise:   sub[ I1(I2(s1)) -> I1(I2(s2)) ]
+//
+// so with applyOuter an outer Retry retries the whole sub-pipeline and an outer timer
+// times it as one step, while without it the sub-pipeline runs only its own policies.
+func (p *Pipeline[T]) ThenPipeline(sub *Pipeline[T], applyOuter bool) *Pipeline[T] {
+	return p.add(stage[T]{skipAll: !applyOuter}, nil, sub.AsStep())
+}
@@ -0,0 +1,156 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// StepFuncContext is a context-aware pipeline step. It should return promptly once ctx is done.
+type StepFuncContext[T any] func(ctx context.Context, input T) (T, error)
+
+// MiddlewareContext wraps a StepFuncContext to provide cross-cutting behavior that needs the
+// execution context, such as cancellation-aware retries.
+type MiddlewareContext[T any] func(next StepFuncContext[T]) StepFuncContext[T]
+
+// UseContext appends a MiddlewareContext to be applied to all steps.
+// It interleaves with middlewares registered by Use in registration order. Plain
+// middlewares registered before it wrap it per call, so any per-step state they set up
+// when wrapping, such as Memoize's cache, does not survive between calls; register
+// stateful plain middlewares after context-aware ones.
+func (p *Pipeline[T]) UseContext(mw MiddlewareContext[T]) *Pipeline[T] {
+	return p.UseContextNamed("", mw)
+}
+
+// UseContextNamed appends a MiddlewareContext under the given name.
+func (p *Pipeline[T]) UseContextNamed(name string, mw MiddlewareContext[T]) *Pipeline[T] {
+	return p.addMiddleware(middleware[T]{name: name, ctx: mw})
+}
+
+// ThenContext appends a StepFuncContext to the pipeline, applying any registered middleware.
+// The step is named "step-N", where N is the number of steps registered before it.
+func (p *Pipeline[T]) ThenContext(step StepFuncContext[T]) *Pipeline[T] {
+	return p.ThenContextNamed("", step)
+}
+
+// ThenContextNamed appends a StepFuncContext under the given name, applying any registered middleware.
+func (p *Pipeline[T]) ThenContextNamed(name string, step StepFuncContext[T]) *Pipeline[T] {
+	return p.add(stage[T]{name: name}, nil, step)
+}
+
+// compose wraps a step, given as exactly one of plain or ctxStep, with mws in reverse
+// registration order so the first registered middleware is outermost. Plain middlewares
+// wrap a plain step once; after a context-aware middleware joins the chain, the plain
+// middlewares outside it are applied per call with next bound to that call's context.
+func compose[T any](plain StepFunc[T], ctxStep StepFuncContext[T], mws []middleware[T]) StepFuncContext[T] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw := mws[i]
+		switch {
+		case mw.ctx != nil:
+			if ctxStep == nil {
+				ctxStep = withContext(plain)
+			}
+			ctxStep = mw.ctx(ctxStep)
+		case ctxStep == nil:
+			plain = mw.plain(plain)
+		default:
+			ctxStep = bindContext(mw.plain, ctxStep)
+		}
+	}
+	if ctxStep == nil {
+		return withContext(plain)
+	}
+	return ctxStep
+}
+
+// withContext adapts a StepFunc to a StepFuncContext that ignores its context.
+func withContext[T any](step StepFunc[T]) StepFuncContext[T] {
+	return func(_ context.Context, input T) (T, error) {
+		return step(input)
+	}
+}
+
+// bindContext applies a plain Middleware around a context-aware step for each call,
+// so that the wrapped StepFunc reaches next with the caller's context.
+func bindContext[T any](mw Middleware[T], next StepFuncContext[T]) StepFuncContext[T] {
+	return func(ctx context.Context, input T) (T, error) {
+		return mw(func(x T) (T, error) { return next(ctx, x) })(input)
+	}
+}
+
+// ParallelContext runs multiple StepFuncContexts on the same input concurrently, then
+// combines their outputs. Each branch receives a child of ctx, so it sees the parent's
+// deadline and values. The first branch error cancels the remaining branches and is
+// returned with the input; if ctx itself is done, ctx.Err() is returned instead. As with
+// Parallel, a nil combiner returns the input once every branch has succeeded.
+func ParallelContext[T any](combiner func([]T) (T, error), steps ...StepFuncContext[T]) StepFuncContext[T] {
+	return func(ctx context.Context, input T) (T, error) {
+		branchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var (
+			wg       sync.WaitGroup
+			once     sync.Once
+			firstErr error
+			results  = make([]T, len(steps))
+		)
+		wg.Add(len(steps))
+		for i, step := range steps {
+			go func(idx int, s StepFuncContext[T]) {
+				defer wg.Done()
+				out, err := s(branchCtx, input)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				results[idx] = out
+			}(i, step)
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return input, err
+		}
+		if firstErr != nil {
+			return input, firstErr
+		}
+		if combiner == nil {
+			return input, nil
+		}
+		return combiner(results)
+	}
+}
+
+// Speculative behaves like Conditional, but starts both branches concurrently while the
+// predicate is evaluated, then returns the chosen branch's result and cancels the other
+// branch's context. This trades compute for latency when the predicate is expensive:
+// every call runs up to twice the work, and the discarded branch must honour
+// cancellation and must not have side effects that matter if its result is dropped. The
+// call does not wait for the discarded branch to return.
+func Speculative[T any](predicate func(T) bool, thenStep, elseStep StepFuncContext[T]) StepFuncContext[T] {
+	return func(ctx context.Context, input T) (T, error) {
+		start := func(step StepFuncContext[T]) (<-chan Result[T], context.CancelFunc) {
+			branchCtx, cancel := context.WithCancel(ctx)
+			done := make(chan Result[T], 1)
+			go func() {
+				v, err := step(branchCtx, input)
+				done <- Result[T]{Value: v, Err: err}
+			}()
+			return done, cancel
+		}
+		thenDone, cancelThen := start(thenStep)
+		defer cancelThen()
+		elseDone, cancelElse := start(elseStep)
+		defer cancelElse()
+
+		chosen := elseDone
+		if predicate(input) {
+			chosen = thenDone
+			cancelElse()
+		} else {
+			cancelThen()
+		}
+		r := <-chosen
+		return r.Value, r.Err
+	}
+}
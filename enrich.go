@@ -0,0 +1,58 @@
+package pipeline
+
+import "time"
+
+// EnrichOption configures the cross-cutting behavior Enrich wraps around a step.
+type EnrichOption func(*enrichConfig)
+
+type enrichConfig struct {
+	attempts int
+	backoff  func(int) time.Duration
+	cacheTTL time.Duration
+	cache    bool
+	timeout  time.Duration
+}
+
+// EnrichRetry retries the step up to attempts times, as Retry does.
+func EnrichRetry(attempts int, backoff func(int) time.Duration) EnrichOption {
+	return func(c *enrichConfig) {
+		c.attempts, c.backoff = attempts, backoff
+	}
+}
+
+// EnrichCache caches successful results for ttl, as Memoize does.
+func EnrichCache(ttl time.Duration) EnrichOption {
+	return func(c *enrichConfig) {
+		c.cache, c.cacheTTL = true, ttl
+	}
+}
+
+// EnrichTimeout bounds each attempt to d, as Timeout does.
+func EnrichTimeout(d time.Duration) EnrichOption {
+	return func(c *enrichConfig) {
+		c.timeout = d
+	}
+}
+
+// Enrich wraps an enrichment step, typically a call to an external service, with the
+// caching, retry and timeout behavior selected by opts. The layers nest as
+// Memoize(Retry(Timeout(fn))): a cache hit skips the call entirely, and the timeout
+// applies to each attempt rather than to all retries together. Without options, fn is
+// returned unchanged.
+func Enrich[T comparable](fn StepFunc[T], opts ...EnrichOption) StepFunc[T] {
+	var cfg enrichConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	step := fn
+	if cfg.timeout > 0 {
+		step = Timeout[T](cfg.timeout)(step)
+	}
+	if cfg.attempts > 1 {
+		step = Retry[T](cfg.attempts, cfg.backoff)(step)
+	}
+	if cfg.cache {
+		step = Memoize[T](cfg.cacheTTL)(step)
+	}
+	return step
+}
@@ -0,0 +1,79 @@
+package pipeline
+
+import "errors"
+
+// Sentinel errors returned by the package. They may be wrapped, so match them with
+// errors.Is rather than by comparison.
+var (
+	// ErrStepTimeout is returned by Timeout and TimeoutContext, and so by steps added with
+	// ThenDeadline and ThenDeadlineContext, when a step does not finish in time.
+	ErrStepTimeout = errors.New("pipeline: step timed out")
+
+	// ErrCircuitOpen is returned by the Breaker middleware, also installed by UseBreaker,
+	// when its CircuitBreaker is open, and carried by the results a RateBreaker stage
+	// replaces while it is open.
+	ErrCircuitOpen = errors.New("pipeline: circuit open")
+
+	// ErrRateLimited is returned by LimitConcurrencyFailFast when its concurrency limit
+	// is reached.
+	ErrRateLimited = errors.New("pipeline: rate limited")
+
+	// ErrMaxIterations is returned by RetryUntil when no attempt produced an output its
+	// done function accepted.
+	ErrMaxIterations = errors.New("pipeline: max iterations reached")
+
+	// ErrBudgetExceeded is returned by the Execute methods before running a step whose
+	// cost, reported through ThenWithCost, would exceed the limit set with WithBudget.
+	ErrBudgetExceeded = errors.New("pipeline: budget exceeded")
+
+	// ErrDeadlineExceeded is returned by ExecuteWithTimeout when the deadline passes and
+	// no onTimeout fallback was given.
+	ErrDeadlineExceeded = errors.New("pipeline: deadline exceeded")
+
+	// ErrEmptyPipeline is returned by the Execute methods and Plan of a pipeline created
+	// with WithRequireSteps that has no steps, and by ParallelRequireSteps without steps.
+	ErrEmptyPipeline = errors.New("pipeline: no steps")
+
+	// ErrTooLarge is returned by MaxSize when a step's input or output exceeds the
+	// configured size, and by AllocGuard when a step allocates more than its cap.
+	ErrTooLarge = errors.New("pipeline: value too large")
+
+	// ErrPipelineStopped is returned by Start and StartConsumers once the pipeline has
+	// been stopped, and by Enqueue once Stop has been called.
+	ErrPipelineStopped = errors.New("pipeline: stopped")
+
+	// ErrNoRoute is returned by WeightedRouter, WeightedRouterContext and
+	// StickyWeightedRouter when no route has a positive weight.
+	ErrNoRoute = errors.New("pipeline: no route")
+
+	// ErrQuorumTooLarge is returned by Quorum when its quorum exceeds the number of
+	// steps.
+	ErrQuorumTooLarge = errors.New("pipeline: quorum larger than steps")
+
+	// ErrUnknownStep is returned by Builder.BuildFromSpec when a spec names a step that
+	// has not been registered.
+	ErrUnknownStep = errors.New("pipeline: unknown step")
+
+	// ErrBatchMismatch is returned by a step made by Batcher when flush returns a
+	// different number of outputs than it was given inputs.
+	ErrBatchMismatch = errors.New("pipeline: batch flush result count mismatch")
+
+	// ErrQueueFull is returned by Enqueue when the pipeline's queue is full and was
+	// created without blocking.
+	ErrQueueFull = errors.New("pipeline: queue full")
+
+	// ErrNoQueue is returned by Enqueue when the pipeline was created without WithQueue.
+	ErrNoQueue = errors.New("pipeline: no queue")
+
+	// ErrTypeMismatch is returned by a function made by Coerce when a value cannot be
+	// converted to the target type.
+	ErrTypeMismatch = errors.New("pipeline: type mismatch")
+
+	// ErrInvalidInput wraps the error of the validator set with WithInputSchema when it
+	// rejects an execution's input or the input given to Plan.
+	ErrInvalidInput = errors.New("pipeline: invalid input")
+
+	// ErrInvalidOutput wraps the error of the validator set with WithOutputSchema when it
+	// rejects an execution's output.
+	ErrInvalidOutput = errors.New("pipeline: invalid output")
+)
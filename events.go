@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Phase identifies the point in a step's execution that a StepEvent reports.
+type Phase string
+
+// The phases reported for each step: PhaseStart before it runs, then PhaseEnd if it
+// succeeded or PhaseError if it failed.
+const (
+	PhaseStart Phase = "start"
+	PhaseEnd   Phase = "end"
+	PhaseError Phase = "error"
+)
+
+// StepEvent describes one phase of one step within an execution. Duration and Err are
+// set for PhaseEnd and PhaseError events only.
+type StepEvent struct {
+	ExecutionID string
+	Step        string
+	Index       int
+	Phase       Phase
+	Duration    time.Duration
+	Err         error
+}
+
+// EventSink receives a StepEvent for every phase of every step. Emit is called
+// synchronously from the executing goroutine, so a sink that blocks stalls the
+// pipeline; sinks shared by concurrent executions must be safe for concurrent use.
+type EventSink interface {
+	Emit(event StepEvent)
+}
+
+// WithEventSink sends a StepEvent to sink for every step phase of every execution.
+func WithEventSink[T any](sink EventSink) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.events = sink
+	}
+}
+
+// emit sends event to the pipeline's sink and to the per-call trace sink in ctx.
+func (p *Pipeline[T]) emit(ctx context.Context, event StepEvent) {
+	if p.events != nil {
+		p.events.Emit(event)
+	}
+	if sink := traceSink(ctx); sink != nil {
+		sink.Emit(event)
+	}
+}
+
+// ChannelSink is an EventSink that sends events on a channel. Emit blocks until the
+// event is received, applying backpressure to the pipeline when the reader falls
+// behind; give the channel a buffer to absorb bursts.
+type ChannelSink chan<- StepEvent
+
+// Emit sends event on the channel.
+func (s ChannelSink) Emit(event StepEvent) {
+	s <- event
+}
+
+// executionIDKey is the context key holding the current execution id.
+type executionIDKey struct{}
+
+// ContextWithExecutionID returns a copy of ctx carrying id, which executions started
+// with that context use as their execution id instead of generating one.
+func ContextWithExecutionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, executionIDKey{}, id)
+}
+
+// ExecutionID returns the execution id carried by ctx, or "" if there is none. Within
+// a step it identifies the current execution when events are enabled.
+func ExecutionID(ctx context.Context) string {
+	id, _ := ctx.Value(executionIDKey{}).(string)
+	return id
+}
+
+// withExecutionID ensures ctx carries an execution id, generating a random one if needed.
+func withExecutionID(ctx context.Context) context.Context {
+	if ExecutionID(ctx) != "" {
+		return ctx
+	}
+	return ContextWithExecutionID(ctx, fmt.Sprintf("%016x", rand.Uint64()))
+}
@@ -0,0 +1,79 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+)
+
+// ExecOption tunes a single ExecuteWithOptions call without changing the pipeline.
+type ExecOption func(*execConfig)
+
+// execConfig holds the per-call settings of ExecuteWithOptions.
+type execConfig struct {
+	ctx     context.Context
+	timeout time.Duration
+	id      string
+	trace   EventSink
+}
+
+// ExecContext runs the call under ctx instead of context.Background().
+func ExecContext(ctx context.Context) ExecOption {
+	return func(c *execConfig) {
+		c.ctx = ctx
+	}
+}
+
+// ExecTimeout bounds the call to d, as if its context had that timeout.
+func ExecTimeout(d time.Duration) ExecOption {
+	return func(c *execConfig) {
+		c.timeout = d
+	}
+}
+
+// ExecID sets the call's execution id, as ContextWithExecutionID does.
+func ExecID(id string) ExecOption {
+	return func(c *execConfig) {
+		c.id = id
+	}
+}
+
+// ExecTrace sends the call's step events to sink, in addition to any sink the pipeline
+// was created with.
+func ExecTrace(sink EventSink) ExecOption {
+	return func(c *execConfig) {
+		c.trace = sink
+	}
+}
+
+// traceSinkKey is the context key holding a per-call EventSink.
+type traceSinkKey struct{}
+
+// traceSink returns the per-call EventSink carried by ctx, or nil.
+func traceSink(ctx context.Context) EventSink {
+	sink, _ := ctx.Value(traceSinkKey{}).(EventSink)
+	return sink
+}
+
+// ExecuteWithOptions runs the pipeline on input like ExecuteContext, with opts
+// overriding defaults for this call only, so slightly different per-request needs do
+// not require separate pipelines. The options are carried in the call's context and
+// never change the shared pipeline.
+func (p *Pipeline[T]) ExecuteWithOptions(input T, opts ...ExecOption) (T, error) {
+	cfg := execConfig{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ctx := cfg.ctx
+	if cfg.id != "" {
+		ctx = ContextWithExecutionID(ctx, cfg.id)
+	}
+	if cfg.trace != nil {
+		ctx = context.WithValue(ctx, traceSinkKey{}, cfg.trace)
+	}
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+	return p.ExecuteContext(ctx, input)
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Stream is a running ExecuteStream. Its results are read from Results, and its
+// progress can be sampled with StreamStats while it runs.
+type Stream[T any] struct {
+	in        <-chan T
+	out       chan Result[T]
+	processed atomic.Int64
+	inFlight  atomic.Int64
+	errors    atomic.Int64
+}
+
+// StreamStats is a snapshot of a Stream's progress.
+type StreamStats struct {
+	Processed int64 // inputs that finished executing, successfully or not
+	InFlight  int64 // inputs currently executing
+	Errors    int64 // inputs whose execution returned an error
+	Backlog   int   // inputs buffered in the input channel, not yet picked up
+}
+
+// ExecuteStream runs every value received from in through the pipeline on workers
+// goroutines (at least one) and delivers a Result for each on the returned Stream.
+// Results arrive in completion order, not input order. The results channel is closed
+// once in is closed and every input has been processed, or once ctx is done.
+func (p *Pipeline[T]) ExecuteStream(ctx context.Context, in <-chan T, workers int) *Stream[T] {
+	s := &Stream[T]{in: in, out: make(chan Result[T])}
+	var wg sync.WaitGroup
+	wg.Add(max(1, workers))
+	for range max(1, workers) {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case input, ok := <-in:
+					if !ok {
+						return
+					}
+					s.inFlight.Add(1)
+					v, err := p.ExecuteContext(ctx, input)
+					s.inFlight.Add(-1)
+					s.processed.Add(1)
+					if err != nil {
+						s.errors.Add(1)
+					}
+					if !send(ctx, s.out, Result[T]{Value: v, Err: err}) {
+						return
+					}
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(s.out)
+	}()
+	return s
+}
+
+// Results returns the channel on which the stream delivers its results.
+func (s *Stream[T]) Results() <-chan Result[T] {
+	return s.out
+}
+
+// StreamStats returns the stream's current counters. Each counter is updated
+// atomically, but they are read one at a time, so the snapshot is only a point-in-time
+// view suitable for monitoring lag, not an exact consistent state.
+func (s *Stream[T]) StreamStats() StreamStats {
+	return StreamStats{
+		Processed: s.processed.Load(),
+		InFlight:  s.inFlight.Load(),
+		Errors:    s.errors.Load(),
+		Backlog:   len(s.in),
+	}
+}
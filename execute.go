@@ -0,0 +1,179 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ExecuteContext runs the pipeline on the given input like Execute, passing ctx to
+// context-aware steps and middleware. The context is checked before each step; once it
+// is done, execution stops and the value produced so far is returned with ctx.Err().
+// Finalizers registered with WithFinalizer run before it returns, on that path too, with
+// the last value and ctx.Err().
+func (p *Pipeline[T]) ExecuteContext(ctx context.Context, input T) (T, error) {
+	out, at, err := p.run(ctx, input, false)
+	if err != nil {
+		p.failures.record(input, at, err)
+	}
+	p.finalize(out, err)
+	return out, err
+}
+
+// ExecuteReverse runs the pipeline's steps in reverse order, last step first, so a
+// pipeline describing resource setup can also serve as its teardown. Only the order
+// of the steps is reversed: each step is still wrapped in the middlewares in the usual
+// nesting, first registered outermost, and pre-steps, post-steps and finalizers run as
+// for Execute. Reverse executions are not kept by a FailureRecorder, whose Replay runs
+// forwards.
+func (p *Pipeline[T]) ExecuteReverse(input T) (T, error) {
+	return p.ExecuteReverseContext(context.Background(), input)
+}
+
+// ExecuteReverseContext is the context-aware form of ExecuteReverse.
+func (p *Pipeline[T]) ExecuteReverseContext(ctx context.Context, input T) (T, error) {
+	out, _, err := p.run(ctx, input, true)
+	p.finalize(out, err)
+	return out, err
+}
+
+// run executes the steps in order, or in reverse order if reverse is set, stopping at
+// the first error or cancellation. It also returns the index of the step it stopped
+// at, or -1 if it stopped before the first step. It reports the execution to the
+// WithSummary callback, if any.
+func (p *Pipeline[T]) run(ctx context.Context, input T, reverse bool) (T, int, error) {
+	if p.events != nil || p.summary != nil || traceSink(ctx) != nil {
+		ctx = withExecutionID(ctx)
+	}
+	if p.summary == nil {
+		return p.runSteps(ctx, input, reverse, nil)
+	}
+	sum := &ExecutionSummary{ExecutionID: ExecutionID(ctx)}
+	start := time.Now()
+	out, at, err := p.runSteps(ctx, input, reverse, sum)
+	sum.Duration, sum.Err = time.Since(start), err
+	p.summary(*sum)
+	return out, at, err
+}
+
+// prepare runs the pre-steps on input and checks the result against the input schema,
+// failing with ErrInvalidInput if it is rejected.
+func (p *Pipeline[T]) prepare(input T) (T, error) {
+	for _, pre := range p.preSteps {
+		input = pre(input)
+	}
+	if p.inputSchema != nil {
+		if err := p.inputSchema(input); err != nil {
+			return input, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+	}
+	return input, nil
+}
+
+// runSteps does the work of run, recording each step's duration in sum if it is not
+// nil.
+func (p *Pipeline[T]) runSteps(ctx context.Context, input T, reverse bool, sum *ExecutionSummary) (T, int, error) {
+	steps := p.compiledStages()
+	if len(steps) == 0 && p.requireSteps {
+		return input, -1, ErrEmptyPipeline
+	}
+	ctx = withRand(ctx, p.rng)
+	var start time.Time
+	if p.richErrors {
+		start = time.Now()
+	}
+	curr, err := p.prepare(input)
+	if err != nil {
+		return curr, -1, err
+	}
+	order := slices.All(steps)
+	if reverse {
+		order = slices.Backward(steps)
+	}
+	deadline, split := ctx.Deadline()
+	split = split && p.budgetSplit != nil
+	var weightLeft float64
+	if split {
+		for _, s := range steps {
+			weightLeft += p.budgetWeight(s)
+		}
+	}
+	spent := 0
+	for i, s := range order {
+		if err = ctx.Err(); err != nil {
+			return curr, i, err
+		}
+		if s.cost != nil {
+			spent += s.cost(curr)
+			if p.budget > 0 && spent > p.budget {
+				return curr, i, ErrBudgetExceeded
+			}
+		}
+		stepCtx, cancel := ctx, context.CancelFunc(func() {})
+		if split {
+			w := p.budgetWeight(s)
+			share := time.Duration(float64(time.Until(deadline)) * w / weightLeft)
+			weightLeft -= w
+			stepCtx, cancel = context.WithTimeout(ctx, share)
+		}
+		in := curr
+		var stepStart time.Time
+		if sum != nil {
+			stepStart = time.Now()
+		}
+		curr, err = p.runStage(stepCtx, i, s, curr)
+		cancel()
+		if sum != nil {
+			sum.Steps = append(sum.Steps, StepTiming{Name: s.name, Duration: time.Since(stepStart)})
+		}
+		if err != nil {
+			if sum != nil {
+				sum.FailedStep = s.name
+			}
+			if p.richErrors {
+				err = ExecutionError{Step: s.name, Index: i, Input: in, Elapsed: time.Since(start), Err: err}
+			}
+			return curr, i, err
+		}
+	}
+	for _, post := range p.postSteps {
+		curr = post(curr)
+	}
+	if p.outputSchema != nil {
+		if err := p.outputSchema(curr); err != nil {
+			return curr, len(steps), fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+		}
+	}
+	return curr, len(steps), nil
+}
+
+// runStage runs a single step, recording it in whichever of error stats, latency
+// histograms, middleware overhead and the event sinks are enabled.
+func (p *Pipeline[T]) runStage(ctx context.Context, index int, s stage[T], input T) (T, error) {
+	if p.latencies == nil && p.overheads == nil && p.events == nil && traceSink(ctx) == nil {
+		out, err := s.step(ctx, input)
+		if err != nil {
+			p.errorStats.record(s.name)
+		}
+		return out, err
+	}
+	event := StepEvent{ExecutionID: ExecutionID(ctx), Step: s.name, Index: index, Phase: PhaseStart}
+	p.emit(ctx, event)
+	start := time.Now()
+	out, err := s.step(ctx, input)
+	event.Duration = time.Since(start)
+	if p.latencies != nil {
+		p.latencies.record(s.name, event.Duration)
+	}
+	if p.overheads != nil {
+		p.overheads.record(s.name, event.Duration)
+	}
+	event.Phase, event.Err = PhaseEnd, err
+	if err != nil {
+		p.errorStats.record(s.name)
+		event.Phase = PhaseError
+	}
+	p.emit(ctx, event)
+	return out, err
+}
@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// stageIDs numbers every stage registered by any pipeline, for StepInfo.ID.
+var stageIDs atomic.Uint64
+
+// StepInfo describes the step a MiddlewareFactory is wrapping. Names need not be
+// unique, so per-step state should be keyed by ID, which identifies the registered step
+// across all pipelines and stays the same when the pipeline is recompiled. Retired is
+// closed once the step has been replaced by Swap, so such state can be dropped.
+type StepInfo struct {
+	Name    string
+	Index   int
+	ID      uint64
+	Retired <-chan struct{}
+}
+
+// MiddlewareFactory creates the Middleware for one step, given its name and position,
+// for middleware that labels its output per step, such as metrics.
+type MiddlewareFactory[T any] func(info StepInfo) Middleware[T]
+
+// UseFactory appends a middleware built by factory separately for each step, to be
+// applied to all steps. The factory is called again whenever the pipeline is
+// recompiled.
+func (p *Pipeline[T]) UseFactory(factory MiddlewareFactory[T]) *Pipeline[T] {
+	return p.UseFactoryNamed("", factory)
+}
+
+// UseFactoryNamed appends a middleware factory under the given name.
+func (p *Pipeline[T]) UseFactoryNamed(name string, factory MiddlewareFactory[T]) *Pipeline[T] {
+	return p.addMiddleware(middleware[T]{name: name, factory: factory})
+}
+
+// StatsDClient is the subset of a StatsD or DogStatsD client that StatsDMiddleware
+// needs, so the package does not depend on any StatsD library. Wrap a client in a small
+// adapter to satisfy it.
+type StatsDClient interface {
+	Timing(name string, d time.Duration)
+	Incr(name string)
+}
+
+// StatsDMiddleware creates a MiddlewareFactory, for UseFactory, that reports every call
+// of every step to client as a "<prefix>.<step>.duration" timing, a "<prefix>.<step>.calls"
+// count and, on failure, a "<prefix>.<step>.errors" count.
+func StatsDMiddleware[T any](client StatsDClient, prefix string) MiddlewareFactory[T] {
+	return func(info StepInfo) Middleware[T] {
+		base := prefix + "." + info.Name
+		return func(next StepFunc[T]) StepFunc[T] {
+			return func(input T) (T, error) {
+				start := time.Now()
+				out, err := next(input)
+				client.Timing(base+".duration", time.Since(start))
+				client.Incr(base + ".calls")
+				if err != nil {
+					client.Incr(base + ".errors")
+				}
+				return out, err
+			}
+		}
+	}
+}
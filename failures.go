@@ -0,0 +1,80 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// FailureRecord describes one failed execution: its input, the index of the step it
+// stopped at (-1 if it failed before running any step) and the error it returned.
+type FailureRecord[T any] struct {
+	Input T
+	Step  int
+	Err   error
+}
+
+// FailureRecorder keeps the most recent failed executions of a pipeline so they can be
+// inspected and reprocessed. A nil *FailureRecorder records nothing.
+type FailureRecorder[T any] struct {
+	p        *Pipeline[T]
+	mu       sync.Mutex
+	capacity int
+	records  []FailureRecord[T]
+}
+
+func (r *FailureRecorder[T]) record(input T, step int, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) == r.capacity {
+		r.records = append(r.records[:0], r.records[1:]...)
+	}
+	r.records = append(r.records, FailureRecord[T]{Input: input, Step: step, Err: err})
+}
+
+// Failures returns the recorded failures, oldest first.
+func (r *FailureRecorder[T]) Failures() []FailureRecord[T] {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FailureRecord[T](nil), r.records...)
+}
+
+// Replay clears the recorded failures and runs each of their inputs through the
+// pipeline again, oldest first, returning the outputs and errors by position. Inputs
+// that fail again are recorded again, so Replay can be repeated once the underlying
+// cause is fixed.
+func (r *FailureRecorder[T]) Replay() ([]T, []error) {
+	if r == nil {
+		return nil, nil
+	}
+	r.mu.Lock()
+	records := r.records
+	r.records = nil
+	r.mu.Unlock()
+	outs := make([]T, len(records))
+	errs := make([]error, len(records))
+	for i, rec := range records {
+		outs[i], errs[i] = r.p.ExecuteContext(context.Background(), rec.Input)
+	}
+	return outs, errs
+}
+
+// WithFailureRecorder records the input, failing step and error of every failed
+// execution, keeping the most recent capacity of them (at least one). The recorder is
+// returned by FailureRecorder.
+func WithFailureRecorder[T any](capacity int) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.failures = &FailureRecorder[T]{p: p, capacity: max(1, capacity)}
+	}
+}
+
+// FailureRecorder returns the pipeline's failure recorder, or nil if it was not created
+// with WithFailureRecorder.
+func (p *Pipeline[T]) FailureRecorder() *FailureRecorder[T] {
+	return p.failures
+}
@@ -0,0 +1,19 @@
+package pipeline
+
+// WithFinalizer registers fn to run after every execution with its final value and
+// error, whether the execution succeeded, failed or was cancelled. Finalizers run in
+// LIFO order, like deferred calls, so cleanup for resources opened by early steps
+// (transactions, temporary files) happens even when a later step fails. Unlike
+// compensation, finalizers run on success too.
+func WithFinalizer[T any](fn func(result T, err error)) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.finalizers = append(p.finalizers, fn)
+	}
+}
+
+// finalize runs the registered finalizers in reverse registration order.
+func (p *Pipeline[T]) finalize(result T, err error) {
+	for i := len(p.finalizers) - 1; i >= 0; i-- {
+		p.finalizers[i](result, err)
+	}
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import "context"
+
+// StepGroup is a sequence of steps that runs as a single step and can be cancelled on
+// its own, without cancelling the pipeline around it. Create one per request to bound
+// an optional block, such as an enrichment, that may be abandoned.
+type StepGroup[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	steps  []StepFuncContext[T]
+}
+
+// Group creates a StepGroup running steps in order.
+func Group[T any](steps ...StepFunc[T]) *StepGroup[T] {
+	ctxSteps := make([]StepFuncContext[T], len(steps))
+	for i, s := range steps {
+		ctxSteps[i] = withContext(s)
+	}
+	return GroupContext(ctxSteps...)
+}
+
+// GroupContext creates a StepGroup running context-aware steps in order. Their context
+// is done when either the caller's context or the group is cancelled, so Cancel can
+// interrupt a step that is already running.
+func GroupContext[T any](steps ...StepFuncContext[T]) *StepGroup[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StepGroup[T]{ctx: ctx, cancel: cancel, steps: steps}
+}
+
+// Cancel cancels the group: steps not yet started are skipped, and context-aware steps
+// that are running see their context done. Cancelling is permanent.
+func (g *StepGroup[T]) Cancel() {
+	g.cancel()
+}
+
+// Context returns the group's own context, which is done once the group is cancelled.
+func (g *StepGroup[T]) Context() context.Context {
+	return g.ctx
+}
+
+// Run runs the group's steps in order as a StepFuncContext, for use with ThenContext.
+// Once the group is cancelled, Run stops and returns the value produced by the steps
+// that completed with a nil error, so the outer pipeline carries on without the rest
+// of the group. A step's error is returned as usual unless the group was cancelled,
+// and cancellation of ctx itself stops Run with ctx.Err().
+func (g *StepGroup[T]) Run(ctx context.Context, input T) (T, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(g.ctx, cancel)
+	defer stop()
+	curr := input
+	for _, s := range g.steps {
+		if err := ctx.Err(); err != nil {
+			return curr, err
+		}
+		if g.ctx.Err() != nil {
+			return curr, nil
+		}
+		out, err := s(runCtx, curr)
+		if err != nil {
+			if ctx.Err() == nil && g.ctx.Err() != nil {
+				return curr, nil
+			}
+			return out, err
+		}
+		curr = out
+	}
+	return curr, nil
+}
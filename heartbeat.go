@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Heartbeat creates a MiddlewareContext that calls beat every interval while the wrapped
+// step runs, for example to renew a lease or a distributed lock held during a slow step.
+// The first beat comes one interval after the step starts. Beating stops when ctx is
+// done or the step returns, and no beat is in progress or started once the step's
+// result has been returned.
+func Heartbeat[T any](interval time.Duration, beat func()) MiddlewareContext[T] {
+	return func(next StepFuncContext[T]) StepFuncContext[T] {
+		return func(ctx context.Context, input T) (T, error) {
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						beat()
+					case <-done:
+						return
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			out, err := next(ctx, input)
+			close(done)
+			wg.Wait()
+			return out, err
+		}
+	}
+}
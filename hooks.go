@@ -0,0 +1,54 @@
+package pipeline
+
+// WithPreStep registers fn to run once per execution, before the first step, for
+// example to normalize the input. Unlike middleware it does not wrap each step.
+// Multiple pre-steps run in registration order.
+func WithPreStep[T any](fn func(T) T) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.preSteps = append(p.preSteps, fn)
+	}
+}
+
+// WithPostStep registers fn to run once per execution, after the last step has
+// succeeded, for example to finalize the output. It does not run when a step fails.
+// Multiple post-steps run in registration order.
+func WithPostStep[T any](fn func(T) T) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.postSteps = append(p.postSteps, fn)
+	}
+}
+
+// WithDefaultInput makes executions substitute def for an input that isZero reports as
+// unset, so optional inputs fall back to a configured default. Go cannot detect zero
+// values generically, so isZero is supplied by the caller. The substitution happens
+// before any pre-steps, regardless of option order.
+func WithDefaultInput[T any](def T, isZero func(T) bool) Option[T] {
+	return func(p *Pipeline[T]) {
+		substitute := func(input T) T {
+			if isZero(input) {
+				return def
+			}
+			return input
+		}
+		p.preSteps = append([]func(T) T{substitute}, p.preSteps...)
+	}
+}
+
+// WithInputSchema registers validate to check each execution's input once, after the
+// pre-steps and before the first step, for example against a request schema. A
+// rejected input fails the execution with ErrInvalidInput wrapping validate's error,
+// without running any step. Unlike Contract, it does not run around every step.
+func WithInputSchema[T any](validate func(T) error) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.inputSchema = validate
+	}
+}
+
+// WithOutputSchema registers validate to check each successful execution's output once,
+// after the post-steps. A rejected output is returned with ErrInvalidOutput wrapping
+// validate's error.
+func WithOutputSchema[T any](validate func(T) error) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.outputSchema = validate
+	}
+}
@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+)
+
+// IdempotencyStore persists the results of steps run by ExactlyOnce, keyed by an
+// idempotency key. Implementations must be safe for concurrent use. The in-memory store
+// from NewMemoryIdempotencyStore is lost on restart, so production code should back it
+// with a durable store such as a database table.
+type IdempotencyStore[T any] interface {
+	// Load returns the result stored under key, and whether there is one.
+	Load(key string) (T, bool, error)
+	// Save stores result under key.
+	Save(key string, result T) error
+}
+
+// ExactlyOnce creates a StepFunc that runs step at most once per key: the key of each
+// input is looked up in store, and if a result is already stored it is returned without
+// calling step, so replaying an input does not repeat its side effect. Otherwise step
+// runs and, if it succeeds, its output is saved under the key. Failed calls save
+// nothing and are run again next time. Concurrent calls for the same key within the
+// process wait for the first one rather than running step alongside it; if step panics
+// in the first call, the waiting calls fail with a PanicError. A store error fails the
+// call with the input unchanged.
+func ExactlyOnce[T any](keyFn func(T) string, store IdempotencyStore[T], step StepFunc[T]) StepFunc[T] {
+	var group onceGroup[T]
+	return func(input T) (T, error) {
+		key := keyFn(input)
+		return group.do(key, func() (T, error) {
+			out, ok, err := store.Load(key)
+			if err != nil {
+				return input, fmt.Errorf("idempotency store: %w", err)
+			}
+			if ok {
+				return out, nil
+			}
+			if out, err = step(input); err != nil {
+				return out, err
+			}
+			if err := store.Save(key, out); err != nil {
+				return input, fmt.Errorf("idempotency store: %w", err)
+			}
+			return out, nil
+		})
+	}
+}
+
+// memoryIdempotencyStore is the map-backed store returned by NewMemoryIdempotencyStore.
+type memoryIdempotencyStore[T any] struct {
+	mu      sync.Mutex
+	results map[string]T
+}
+
+// NewMemoryIdempotencyStore returns an in-memory IdempotencyStore, suitable for tests and
+// for deduplicating within a single process.
+func NewMemoryIdempotencyStore[T any]() IdempotencyStore[T] {
+	return &memoryIdempotencyStore[T]{results: make(map[string]T)}
+}
+
+func (s *memoryIdempotencyStore[T]) Load(key string) (T, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.results[key]
+	return result, ok, nil
+}
+
+func (s *memoryIdempotencyStore[T]) Save(key string, result T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[key] = result
+	return nil
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MiddlewareCount returns the number of middlewares registered with Use and UseContext.
+func (p *Pipeline[T]) MiddlewareCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.middlewares)
+}
+
+// MiddlewareNames returns the names of the registered middlewares in registration order.
+func (p *Pipeline[T]) MiddlewareNames() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, len(p.middlewares))
+	for i, mw := range p.middlewares {
+		names[i] = mw.name
+	}
+	return names
+}
+
+// String returns a human-readable summary of the pipeline's steps and middlewares, e.g.
+// "Pipeline[2 steps: parse, save; middleware: retry]".
+func (p *Pipeline[T]) String() string {
+	steps := p.stages()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b strings.Builder
+	noun := "steps"
+	if len(steps) == 1 {
+		noun = "step"
+	}
+	fmt.Fprintf(&b, "Pipeline[%d %s", len(steps), noun)
+	for i, s := range steps {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(s.name)
+	}
+	if len(p.middlewares) > 0 {
+		b.WriteString("; middleware: ")
+		for i, mw := range p.middlewares {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(mw.name)
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Equal reports whether p and other have the same structure: the same step names in
+// the same order and the same middleware names in the same order. It compares
+// metadata only, not behavior, since the step and middleware functions themselves are
+// not comparable; it suits golden tests of pipelines built programmatically.
+func (p *Pipeline[T]) Equal(other *Pipeline[T]) bool {
+	if p == other {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	return slices.EqualFunc(p.stages(), other.stages(), func(a, b stage[T]) bool { return a.name == b.name }) &&
+		slices.Equal(p.MiddlewareNames(), other.MiddlewareNames())
+}
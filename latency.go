@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram buckets are log-linear, as in HDR histograms: values below 32ns get exact
+// buckets, and every power-of-two range above is split into 16 linear sub-buckets,
+// bounding the relative error of a reported percentile to about 6%.
+const (
+	subBucketBits = 4
+	subBuckets    = 1 << subBucketBits
+	bucketCount   = (64-subBucketBits-1)*subBuckets + 2*subBuckets
+)
+
+// latencyHistogram counts durations into log-linear buckets using atomic counters, so
+// concurrent executions record without locking.
+type latencyHistogram struct {
+	counts [bucketCount]atomic.Uint64
+}
+
+func bucketOf(ns uint64) int {
+	shift := bits.Len64(ns) - subBucketBits - 1
+	if shift < 0 {
+		shift = 0
+	}
+	return shift*subBuckets + int(ns>>uint(shift))
+}
+
+// bucketUpperBound returns the largest duration, in nanoseconds, counted in bucket i.
+func bucketUpperBound(i int) uint64 {
+	if i < 2*subBuckets {
+		return uint64(i)
+	}
+	shift := uint(i/subBuckets - 1)
+	m := uint64(i - int(shift)*subBuckets)
+	return (m+1)<<shift - 1
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[bucketOf(uint64(d))].Add(1)
+}
+
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	var total uint64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen uint64
+	for i := range h.counts {
+		seen += h.counts[i].Load()
+		if seen >= rank {
+			return time.Duration(bucketUpperBound(i))
+		}
+	}
+	return time.Duration(bucketUpperBound(bucketCount - 1))
+}
+
+// latencies holds one histogram per step name. A nil *latencies records nothing.
+type latencies struct {
+	steps sync.Map // step name -> *latencyHistogram
+}
+
+func (l *latencies) record(name string, d time.Duration) {
+	if l == nil {
+		return
+	}
+	h, ok := l.steps.Load(name)
+	if !ok {
+		h, _ = l.steps.LoadOrStore(name, new(latencyHistogram))
+	}
+	h.(*latencyHistogram).record(d)
+}
+
+// WithLatencyHistogram enables per-step latency histograms, queried with
+// StepLatencyPercentile.
+func WithLatencyHistogram[T any]() Option[T] {
+	return func(p *Pipeline[T]) {
+		p.latencies = new(latencies)
+	}
+}
+
+// StepLatencyPercentile returns the q-th quantile (0 <= q <= 1, e.g. 0.99 for p99) of
+// the named step's latency across all executions so far. Results are approximate to
+// within about 6%. It returns 0 if the step has no samples or the pipeline was not
+// created with WithLatencyHistogram.
+func (p *Pipeline[T]) StepLatencyPercentile(name string, q float64) time.Duration {
+	if p.latencies == nil {
+		return 0
+	}
+	h, ok := p.latencies.steps.Load(name)
+	if !ok {
+		return 0
+	}
+	return h.(*latencyHistogram).percentile(q)
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Service is a background resource owned by a pipeline, such as a cache janitor or a
+// pool of stream workers. Its lifetime follows the pipeline's Start and Stop.
+type Service interface {
+	Start() error
+	Stop(ctx context.Context) error
+}
+
+// lifecycle tracks the services owned by a pipeline and whether they are running.
+type lifecycle struct {
+	mu       sync.Mutex
+	services []Service
+	started  bool
+	stopped  bool
+}
+
+// WithService registers a Service that Start starts and Stop drains.
+func WithService[T any](svc Service) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.lifecycle.services = append(p.lifecycle.services, svc)
+	}
+}
+
+// Start starts every registered Service in registration order. If one fails to start,
+// the services already started are stopped again and the error is returned. Calling
+// Start on a running pipeline is a no-op; once stopped, a pipeline cannot be restarted.
+func (p *Pipeline[T]) Start() error {
+	l := &p.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		return ErrPipelineStopped
+	}
+	if l.started {
+		return nil
+	}
+	for i, svc := range l.services {
+		if err := svc.Start(); err != nil {
+			stopAll(context.Background(), l.services[:i])
+			return err
+		}
+	}
+	l.started = true
+	return nil
+}
+
+// Stop stops every registered Service in reverse registration order, waiting at most
+// until ctx is done for each one to drain, and returns their errors joined. Stop is
+// idempotent: calls after the first return nil.
+func (p *Pipeline[T]) Stop(ctx context.Context) error {
+	l := &p.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		return nil
+	}
+	l.stopped = true
+	if !l.started {
+		p.queue.shutdown()
+		return nil
+	}
+	return stopAll(ctx, l.services)
+}
+
+// stopAll stops services in reverse order and joins their errors.
+func stopAll(ctx context.Context, services []Service) error {
+	var errs []error
+	for i := len(services) - 1; i >= 0; i-- {
+		if err := services[i].Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
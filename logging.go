@@ -0,0 +1,49 @@
+package pipeline
+
+import "sync/atomic"
+
+// Logger is the logging interface used by the logging middlewares. *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// LogSampled creates a Middleware that logs a step call, with its input, output and
+// error, only for every everyN-th call, starting with the first, so a busy pipeline
+// cannot flood the log. The count is shared by every step and goroutine using the
+// middleware. An everyN of one or less logs every call.
+func LogSampled[T any](logger Logger, everyN int) Middleware[T] {
+	var calls atomic.Uint64
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			out, err := next(input)
+			if n := calls.Add(1); sampled(n, everyN) {
+				logger.Printf("pipeline: call %d: input %v, output %v, err %v", n, input, out, err)
+			}
+			return out, err
+		}
+	}
+}
+
+// LogSampledErrors is like LogSampled but counts and logs only failed calls, logging the
+// first error immediately and then every everyN-th one.
+func LogSampledErrors[T any](logger Logger, everyN int) Middleware[T] {
+	var failures atomic.Uint64
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			out, err := next(input)
+			if err == nil {
+				return out, nil
+			}
+			if n := failures.Add(1); sampled(n, everyN) {
+				logger.Printf("pipeline: error %d: input %v: %v", n, input, err)
+			}
+			return out, err
+		}
+	}
+}
+
+// sampled reports whether the n-th occurrence, counting from one, is logged.
+func sampled(n uint64, everyN int) bool {
+	return everyN <= 1 || (n-1)%uint64(everyN) == 0
+}
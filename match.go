@@ -0,0 +1,52 @@
+package pipeline
+
+import "slices"
+
+// Matcher builds a StepFunc from an ordered list of predicate cases, as a readable
+// alternative to nested Conditionals. Create one with Match.
+type Matcher[T any] struct {
+	cases []matchCase[T]
+	def   StepFunc[T]
+}
+
+type matchCase[T any] struct {
+	predicate func(T) bool
+	step      StepFunc[T]
+}
+
+// Match starts an empty Matcher.
+func Match[T any]() *Matcher[T] {
+	return &Matcher[T]{}
+}
+
+// Case adds a case that runs step when predicate holds and no earlier case matched.
+func (m *Matcher[T]) Case(predicate func(T) bool, step StepFunc[T]) *Matcher[T] {
+	m.cases = append(m.cases, matchCase[T]{predicate, step})
+	return m
+}
+
+// Default sets the step run when no case matches. Without one, unmatched inputs pass
+// through unchanged.
+func (m *Matcher[T]) Default(step StepFunc[T]) *Matcher[T] {
+	m.def = step
+	return m
+}
+
+// Build returns a StepFunc that evaluates the cases in the order they were added and
+// runs the step of the first that matches. Later changes to the Matcher do not affect
+// steps already built.
+func (m *Matcher[T]) Build() StepFunc[T] {
+	cases := slices.Clone(m.cases)
+	def := m.def
+	if def == nil {
+		def = Identity[T]()
+	}
+	return func(input T) (T, error) {
+		for _, c := range cases {
+			if c.predicate(input) {
+				return c.step(input)
+			}
+		}
+		return def(input)
+	}
+}
@@ -0,0 +1,172 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores memoized step results. Implementations must be safe for concurrent use;
+// NewMemoryCache provides an in-memory one, and others can back Memoize with a shared
+// store.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored under key, if it is present and not expired.
+	Get(key K) (V, bool)
+	// Set stores value under key for ttl; a ttl of zero or less keeps it forever.
+	Set(key K, value V, ttl time.Duration)
+	// Delete removes key.
+	Delete(key K)
+	// Clear removes every key.
+	Clear()
+}
+
+// memoEntry is a cached step result and the time it expires.
+type memoEntry[V any] struct {
+	out     V
+	expires time.Time
+}
+
+// memoryCache is the map-backed Cache returned by NewMemoryCache.
+type memoryCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]memoEntry[V]
+}
+
+// NewMemoryCache returns an in-memory Cache. Expired entries are dropped when they are
+// next looked up.
+func NewMemoryCache[K comparable, V any]() Cache[K, V] {
+	return &memoryCache[K, V]{entries: make(map[K]memoEntry[V])}
+}
+
+func (c *memoryCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && !e.expires.IsZero() && !time.Now().Before(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	return e.out, ok
+}
+
+func (c *memoryCache[K, V]) Set(key K, value V, ttl time.Duration) {
+	e := memoEntry[V]{out: value}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+}
+
+func (c *memoryCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+func (c *memoryCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// Memoize creates a Middleware that caches successful step outputs keyed by input for
+// ttl; a ttl of zero or less caches forever. Failed calls are not cached. Expired
+// entries are dropped when they are next looked up. Use NewMemoize to be able to
+// invalidate entries.
+func Memoize[T comparable](ttl time.Duration) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return memoized(NewMemoryCache[T, T](), ttl, next)
+	}
+}
+
+// memoized wraps next so that its successful outputs are cached in cache for ttl.
+func memoized[T comparable](cache Cache[T, T], ttl time.Duration, next StepFunc[T]) StepFunc[T] {
+	return func(input T) (T, error) {
+		if out, ok := cache.Get(input); ok {
+			return out, nil
+		}
+		out, err := next(input)
+		if err != nil {
+			return out, err
+		}
+		cache.Set(input, out, ttl)
+		return out, nil
+	}
+}
+
+// MemoizeHandle is a memoizing middleware whose cached results can be evicted before
+// they expire, for example when upstream data changes. Each step it wraps has its own
+// cache, keyed by the step's StepInfo ID, which survives the pipeline being recompiled
+// and is dropped once the step is replaced by Swap.
+type MemoizeHandle[T comparable] struct {
+	ttl      time.Duration
+	newCache func() Cache[T, T]
+
+	mu     sync.Mutex
+	caches map[uint64]stepCache[T]
+}
+
+// stepCache is the cache of one step and the channel closed when the step is retired.
+type stepCache[T comparable] struct {
+	cache   Cache[T, T]
+	retired <-chan struct{}
+}
+
+// NewMemoize creates a MemoizeHandle caching results for ttl, as Memoize does, in caches
+// made by newCache, one per step; a nil newCache uses NewMemoryCache. Install it with
+// UseFactory(m.Middleware()).
+func NewMemoize[T comparable](ttl time.Duration, newCache func() Cache[T, T]) *MemoizeHandle[T] {
+	if newCache == nil {
+		newCache = NewMemoryCache[T, T]
+	}
+	return &MemoizeHandle[T]{ttl: ttl, newCache: newCache, caches: make(map[uint64]stepCache[T])}
+}
+
+// Middleware returns the MiddlewareFactory that memoizes each step.
+func (m *MemoizeHandle[T]) Middleware() MiddlewareFactory[T] {
+	return func(info StepInfo) Middleware[T] {
+		m.mu.Lock()
+		m.prune()
+		c, ok := m.caches[info.ID]
+		if !ok {
+			c = stepCache[T]{cache: m.newCache(), retired: info.Retired}
+			m.caches[info.ID] = c
+		}
+		m.mu.Unlock()
+		return func(next StepFunc[T]) StepFunc[T] {
+			return memoized(c.cache, m.ttl, next)
+		}
+	}
+}
+
+// prune drops the caches of retired steps. m.mu must be held.
+func (m *MemoizeHandle[T]) prune() {
+	for id, c := range m.caches {
+		select {
+		case <-c.retired:
+			delete(m.caches, id)
+		default:
+		}
+	}
+}
+
+// Invalidate evicts the cached result for input from every step.
+func (m *MemoizeHandle[T]) Invalidate(input T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune()
+	for _, c := range m.caches {
+		c.cache.Delete(input)
+	}
+}
+
+// Clear evicts every cached result.
+func (m *MemoizeHandle[T]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune()
+	for _, c := range m.caches {
+		c.cache.Clear()
+	}
+}
@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Meta holds key/value metadata, such as non-fatal warnings, emitted by steps.
+type Meta map[string]any
+
+// StepFuncMeta is a pipeline step that may attach Meta to its result without altering T.
+type StepFuncMeta[T any] func(T) (T, Meta, error)
+
+// metaKey is the context key under which ExecuteWithMeta stores its collector.
+type metaKey struct{}
+
+// metaCollector accumulates Meta across the steps of one execution.
+type metaCollector struct {
+	mu   sync.Mutex
+	meta Meta
+}
+
+func (c *metaCollector) merge(m Meta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range m {
+		c.meta[k] = v
+	}
+}
+
+// ThenMeta appends a StepFuncMeta to the pipeline, applying any registered middleware.
+// Its Meta is collected by ExecuteWithMeta and discarded by the other Execute methods.
+func (p *Pipeline[T]) ThenMeta(step StepFuncMeta[T]) *Pipeline[T] {
+	return p.ThenContext(func(ctx context.Context, input T) (T, error) {
+		out, m, err := step(input)
+		if c, ok := ctx.Value(metaKey{}).(*metaCollector); ok {
+			c.merge(m)
+		}
+		return out, err
+	})
+}
+
+// ExecuteWithMeta runs the pipeline like Execute and also returns the Meta emitted by
+// steps added with ThenMeta. When steps emit the same key, the later value wins.
+// Meta emitted before a failure is returned alongside the error.
+func (p *Pipeline[T]) ExecuteWithMeta(input T) (T, Meta, error) {
+	c := &metaCollector{meta: make(Meta)}
+	ctx := context.WithValue(context.Background(), metaKey{}, c)
+	out, err := p.ExecuteContext(ctx, input)
+	return out, c.meta, err
+}
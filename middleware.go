@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+)
+
+// Contract creates a Middleware that checks pre against a step's input before calling it
+// and post against its output afterwards, returning the failing check's error. Either
+// check may be nil to skip it. Errors from the step itself are returned unchanged.
+func Contract[T any](pre func(T) error, post func(T) error) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			if pre != nil {
+				if err := pre(input); err != nil {
+					return input, err
+				}
+			}
+			out, err := next(input)
+			if err != nil {
+				return out, err
+			}
+			if post != nil {
+				if err := post(out); err != nil {
+					return out, err
+				}
+			}
+			return out, nil
+		}
+	}
+}
+
+// MaxSize creates a Middleware that rejects a step's input, and then its output, when
+// size reports more than max, returning ErrTooLarge. The size function defines what
+// size means for T, such as a byte length or an element count.
+func MaxSize[T any](size func(T) int, max int) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			if n := size(input); n > max {
+				return input, fmt.Errorf("%w: input size %d exceeds %d", ErrTooLarge, n, max)
+			}
+			out, err := next(input)
+			if err != nil {
+				return out, err
+			}
+			if n := size(out); n > max {
+				return out, fmt.Errorf("%w: output size %d exceeds %d", ErrTooLarge, n, max)
+			}
+			return out, nil
+		}
+	}
+}
+
+// When creates a Middleware that applies mw only to inputs matching predicate; other
+// inputs go straight to the step. This limits costly middlewares such as AllocGuard to
+// the inputs that need them.
+func When[T any](predicate func(T) bool, mw Middleware[T]) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		wrapped := mw(next)
+		return func(input T) (T, error) {
+			if predicate(input) {
+				return wrapped(input)
+			}
+			return next(input)
+		}
+	}
+}
+
+// WhenContext creates a MiddlewareContext, for UseContext, that applies mw only to calls
+// whose context satisfies predicate, such as enabling detailed tracing for requests
+// carrying a debug flag. The predicate is checked on every step call. It returns a
+// MiddlewareContext rather than a Middleware because only context-aware middleware can
+// see the context. mw is applied per call, as plain middlewares inside context-aware
+// ones are, so state it sets up when wrapping does not survive between calls.
+func WhenContext[T any](predicate func(context.Context) bool, mw Middleware[T]) MiddlewareContext[T] {
+	return func(next StepFuncContext[T]) StepFuncContext[T] {
+		wrapped := bindContext(mw, next)
+		return func(ctx context.Context, input T) (T, error) {
+			if predicate(ctx) {
+				return wrapped(ctx, input)
+			}
+			return next(ctx, input)
+		}
+	}
+}
+
+// ThenSkipMiddleware appends a StepFunc that bypasses some of the registered
+// middlewares: those at the given registration indices, or all of them if no index is
+// given. This suits steps that must not be retried or logged, for example because they
+// are already idempotent and noisy.
+func (p *Pipeline[T]) ThenSkipMiddleware(step StepFunc[T], skip ...int) *Pipeline[T] {
+	return p.add(stage[T]{skipAll: len(skip) == 0, skip: skip}, step, nil)
+}
+
+// Stack composes mws into a single Middleware, so a standard set such as recover, log,
+// metrics and retry can be shared and installed with one Use call. As with Use, the
+// first middleware listed is outermost: Use(Stack(a, b)) behaves like Use(a).Use(b).
+func Stack[T any](mws ...Middleware[T]) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// LimitConcurrency creates a Middleware that allows at most max concurrent calls of
+// the steps it wraps, across all executions; further calls block until a slot frees
+// up. A max below one is treated as one. The limit belongs to the returned Middleware,
+// so every step it wraps shares it; create one per step to limit steps separately.
+func LimitConcurrency[T any](max int) Middleware[T] {
+	return limitConcurrency[T](max, true)
+}
+
+// LimitConcurrencyFailFast behaves like LimitConcurrency, but a call made while the
+// limit is reached fails immediately with ErrRateLimited and the input unchanged.
+func LimitConcurrencyFailFast[T any](max int) Middleware[T] {
+	return limitConcurrency[T](max, false)
+}
+
+func limitConcurrency[T any](limit int, block bool) Middleware[T] {
+	sem := make(chan struct{}, max(1, limit))
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			if block {
+				sem <- struct{}{}
+			} else {
+				select {
+				case sem <- struct{}{}:
+				default:
+					return input, ErrRateLimited
+				}
+			}
+			defer func() { <-sem }()
+			return next(input)
+		}
+	}
+}
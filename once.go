@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// onceGroup tracks the in-flight call for each key.
+type onceGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*onceCall[T]
+}
+
+type onceCall[T any] struct {
+	done chan struct{}
+	out  T
+	err  error
+}
+
+// do runs fn unless a call for key is already running, in which case it waits for that
+// call and returns its result. If fn panics, the waiters get a PanicError and the panic
+// is re-raised in the caller that ran fn.
+func (g *onceGroup[T]) do(key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.out, c.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*onceCall[T])
+	}
+	c := &onceCall[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		v := recover()
+		if v != nil {
+			var zero T
+			c.out, c.err = zero, PanicError{Value: v, Stack: debug.Stack()}
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+		if v != nil {
+			panic(v)
+		}
+	}()
+	c.out, c.err = fn()
+	return c.out, c.err
+}
+
+// ExecuteOnce runs the pipeline on input like Execute, but allows only one execution
+// per key at a time: a call made while an execution for the same key is running blocks
+// until it finishes and returns its result, ignoring its own input. Once the execution
+// finishes, the next call for the key runs the pipeline again. If the execution panics,
+// the blocked calls return a PanicError and the panic continues in the first call. Use
+// it to keep concurrent triggers for the same aggregate from processing it twice at
+// once.
+func (p *Pipeline[T]) ExecuteOnce(key string, input T) (T, error) {
+	return p.once.do(key, func() (T, error) {
+		return p.Execute(input)
+	})
+}
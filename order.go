@@ -0,0 +1,9 @@
+package pipeline
+
+// ThenOrdered appends a StepFunc with an ordering priority, applying any registered
+// Middleware. Steps run in ascending order; steps with equal order, including all steps
+// added with Then (order 0), run in registration order. This lets independently
+// registered plugins control sequencing regardless of registration order.
+func (p *Pipeline[T]) ThenOrdered(order int, step StepFunc[T]) *Pipeline[T] {
+	return p.add(stage[T]{order: order}, step, nil)
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stepOverhead accumulates a step's wrapped and bare running time across executions.
+type stepOverhead struct {
+	wrapped atomic.Int64 // nanoseconds spent in the step including its middlewares
+	bare    atomic.Int64 // nanoseconds spent in the step function itself
+	calls   atomic.Int64
+}
+
+// overheads holds one stepOverhead per step name. A nil *overheads records nothing.
+type overheads struct {
+	steps sync.Map // step name -> *stepOverhead
+}
+
+func (o *overheads) forStep(name string) *stepOverhead {
+	s, ok := o.steps.Load(name)
+	if !ok {
+		s, _ = o.steps.LoadOrStore(name, new(stepOverhead))
+	}
+	return s.(*stepOverhead)
+}
+
+// timeBare wraps whichever of plain or ctxStep is set so that its own running time is
+// added to the named step's bare total. It returns both unchanged when o is nil.
+func timeBare[T any](o *overheads, name string, plain StepFunc[T], ctxStep StepFuncContext[T]) (StepFunc[T], StepFuncContext[T]) {
+	if o == nil {
+		return plain, ctxStep
+	}
+	s := o.forStep(name)
+	if ctxStep != nil {
+		return nil, func(ctx context.Context, input T) (T, error) {
+			defer s.addBare(time.Now())
+			return ctxStep(ctx, input)
+		}
+	}
+	return func(input T) (T, error) {
+		defer s.addBare(time.Now())
+		return plain(input)
+	}, nil
+}
+
+func (s *stepOverhead) addBare(start time.Time) {
+	s.bare.Add(int64(time.Since(start)))
+}
+
+// record adds one wrapped execution of the step.
+func (o *overheads) record(name string, d time.Duration) {
+	s := o.forStep(name)
+	s.wrapped.Add(int64(d))
+	s.calls.Add(1)
+}
+
+// WithMiddlewareOverhead enables measurement of the time each step spends in its
+// middlewares, queried with MiddlewareOverhead. Every step is timed twice, around its
+// middlewares and on its own, so leave it off in hot paths once tuning is done.
+func WithMiddlewareOverhead[T any]() Option[T] {
+	return func(p *Pipeline[T]) {
+		p.overheads = new(overheads)
+	}
+}
+
+// MiddlewareOverhead returns the average time per execution that the named step spent
+// in its middlewares rather than in the step itself: its mean wrapped duration minus
+// its mean bare duration. Middleware that calls the step several times, such as Retry,
+// counts each call towards the bare duration. It returns 0 if the step has not run or
+// the pipeline was not created with WithMiddlewareOverhead.
+func (p *Pipeline[T]) MiddlewareOverhead(name string) time.Duration {
+	if p.overheads == nil {
+		return 0
+	}
+	s, ok := p.overheads.steps.Load(name)
+	if !ok {
+		return 0
+	}
+	o := s.(*stepOverhead)
+	calls := o.calls.Load()
+	if calls == 0 {
+		return 0
+	}
+	return time.Duration(max(0, o.wrapped.Load()-o.bare.Load()) / calls)
+}
@@ -1,7 +1,14 @@
 package pipeline
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // StepFunc is a pipeline step that transforms an input of type T, optionally returning an error.
@@ -10,48 +17,275 @@ type StepFunc[T any] func(T) (T, error)
 // Middleware is a function that wraps a StepFunc to provide cross-cutting behavior.
 type Middleware[T any] func(next StepFunc[T]) StepFunc[T]
 
+// Option configures a Pipeline when it is created with New.
+type Option[T any] func(*Pipeline[T])
+
 // Pipeline chains a series of StepFuncs to process data in sequence.
 type Pipeline[T any] struct {
-	steps       []StepFunc[T]
-	middlewares []Middleware[T]
+	mu           sync.Mutex                 // serializes changes to steps and middlewares
+	steps        atomic.Pointer[[]stage[T]] // raw steps, in execution order
+	compiled     atomic.Pointer[[]stage[T]] // steps wrapped in middlewares; nil when stale
+	middlewares  []middleware[T]
+	mwCount      int // middlewares ever registered, including removed ones
+	errorStats   *errorStats
+	failures     *FailureRecorder[T]
+	queue        *queue[T]
+	latencies    *latencies
+	overheads    *overheads
+	events       EventSink
+	lifecycle    lifecycle
+	budget       int
+	requireSteps bool
+	richErrors   bool
+	rng          *rand.Rand
+	budgetSplit  map[string]float64
+	inputSchema  func(T) error
+	outputSchema func(T) error
+	summary      func(ExecutionSummary)
+	finalizers   []func(T, error)
+	preSteps     []func(T) T
+	postSteps    []func(T) T
+	once         onceGroup[T]
+}
+
+// stage is a registered step together with its identity, name, ordering priority,
+// optional cost, tags, branch chooser and the middlewares it opts out of. It keeps the raw step, given
+// as exactly one of plain or ctxStep; step, the chain composed with the middlewares, is
+// only set on the compiled copy. Every stage runs on the context-aware path; plain steps
+// simply ignore the context.
+type stage[T any] struct {
+	id      uint64        // unique across pipelines, stable until the stage is replaced
+	retired chan struct{} // closed when Swap replaces the stage
+	name    string
+	order   int
+	cost    func(T) int
+	skipAll bool  // bypass every middleware
+	skip    []int // registration indices of middlewares to bypass
+	tags    map[string]string
+	choose  func(T) string // reports the branch taken, for steps added with ThenBranch
+	plain   StepFunc[T]
+	ctxStep StepFuncContext[T]
+	step    StepFuncContext[T]
 }
 
-// New creates a new, empty Pipeline for type T.
-func New[T any]() *Pipeline[T] {
-	return &Pipeline[T]{
-		steps:       make([]StepFunc[T], 0),
-		middlewares: make([]Middleware[T], 0),
+// applies reports whether mw wraps the stage.
+func (s stage[T]) applies(mw middleware[T]) bool {
+	if mw.tagKey != "" {
+		if v, ok := s.tags[mw.tagKey]; !ok || v != mw.tagValue {
+			return false
+		}
 	}
+	return !s.skipAll && !slices.Contains(s.skip, mw.index)
 }
 
-// Use appends a Middleware to be applied to all subsequent steps.
-func (p *Pipeline[T]) Use(mw Middleware[T]) *Pipeline[T] {
-	p.middlewares = append(p.middlewares, mw)
+// middleware is a registered Middleware together with its name, registration index and,
+// for UseBreaker, its circuit breaker and, for UseForTag, the tag a step must carry for
+// it to apply. It holds exactly one of a plain Middleware, a
+// context-aware one, or a factory producing a plain Middleware per step.
+type middleware[T any] struct {
+	name    string
+	index   int
+	plain   Middleware[T]
+	ctx     MiddlewareContext[T]
+	factory MiddlewareFactory[T]
+	breaker *CircuitBreaker
+
+	tagKey, tagValue string
+}
+
+// New creates a new, empty Pipeline for type T, applying any options.
+func New[T any](opts ...Option[T]) *Pipeline[T] {
+	p := &Pipeline[T]{
+		middlewares: make([]middleware[T], 0),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
 	return p
 }
 
+// FromSteps creates a Pipeline from pre-made steps and middlewares in one call. It is
+// the same as calling Use for each middleware and then Then for each step, so the first
+// middleware is outermost and the steps are named "step-N" in order.
+func FromSteps[T any](steps []StepFunc[T], mws ...Middleware[T]) *Pipeline[T] {
+	p := New[T]()
+	for _, mw := range mws {
+		p.Use(mw)
+	}
+	for _, step := range steps {
+		p.Then(step)
+	}
+	return p
+}
+
+// Use appends a Middleware to be applied to all steps, including those already added.
+// The middleware is named "mw-N", where N is its registration index, counting removed
+// middlewares.
+func (p *Pipeline[T]) Use(mw Middleware[T]) *Pipeline[T] {
+	return p.UseNamed("", mw)
+}
+
+// UseNamed appends a Middleware under the given name, to be applied to all steps.
+// The name identifies the middleware in introspection such as MiddlewareNames.
+func (p *Pipeline[T]) UseNamed(name string, mw Middleware[T]) *Pipeline[T] {
+	return p.addMiddleware(middleware[T]{name: name, plain: mw})
+}
+
 // Then appends a StepFunc to the pipeline, applying any registered Middleware.
+// The step is named "step-N", where N is the number of steps registered before it.
 func (p *Pipeline[T]) Then(step StepFunc[T]) *Pipeline[T] {
-	// Apply middlewares in reverse registration order
-	for i := len(p.middlewares) - 1; i >= 0; i-- {
-		step = p.middlewares[i](step)
+	return p.ThenNamed("", step)
+}
+
+// ThenNamed appends a StepFunc under the given name, applying any registered Middleware.
+// The name identifies the step in diagnostics such as ErrorStats.
+func (p *Pipeline[T]) ThenNamed(name string, step StepFunc[T]) *Pipeline[T] {
+	return p.add(stage[T]{name: name}, step, nil)
+}
+
+// add registers a step, given as exactly one of plain or ctxStep. An empty s.name
+// defaults to "step-N". The stage is inserted after every stage whose order is not
+// greater than its own, keeping the steps sorted by order and in insertion order within
+// the same order. Executions already running keep the previous steps, since the slice
+// is replaced rather than modified.
+func (p *Pipeline[T]) add(s stage[T], plain StepFunc[T], ctxStep StepFuncContext[T]) *Pipeline[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	steps := p.stages()
+	if s.name == "" {
+		s.name = fmt.Sprintf("step-%d", len(steps))
+	}
+	s.id, s.retired = stageIDs.Add(1), make(chan struct{})
+	s.plain, s.ctxStep = timeBare(p.overheads, s.name, plain, ctxStep)
+	i := len(steps)
+	for i > 0 && steps[i-1].order > s.order {
+		i--
+	}
+	next := slices.Insert(slices.Clone(steps), i, s)
+	p.steps.Store(&next)
+	p.compiled.Store(nil)
+	return p
+}
+
+// wrapStage sets s.step, the stage at the given index, to its raw step wrapped in the
+// registered middlewares that apply to it.
+func (p *Pipeline[T]) wrapStage(s *stage[T], index int) {
+	var mws []middleware[T]
+	for _, mw := range p.middlewares {
+		if !s.applies(mw) {
+			continue
+		}
+		if mw.factory != nil {
+			mw.plain = mw.factory(StepInfo{Name: s.name, Index: index, ID: s.id, Retired: s.retired})
+		}
+		mws = append(mws, mw)
 	}
-	p.steps = append(p.steps, step)
+	s.step = compose(s.plain, s.ctxStep, mws)
+}
+
+// addMiddleware registers mw, defaulting an empty name to "mw-N".
+func (p *Pipeline[T]) addMiddleware(mw middleware[T]) *Pipeline[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	mw.index = p.mwCount
+	p.mwCount++
+	if mw.name == "" {
+		mw.name = fmt.Sprintf("mw-%d", mw.index)
+	}
+	p.middlewares = append(p.middlewares, mw)
+	p.compiled.Store(nil)
 	return p
 }
 
+// RemoveMiddleware removes the first registered middleware with the given name from
+// every step, reporting whether one was found. The other middlewares keep their order
+// and registration indices. Executions already running keep the previous steps.
+func (p *Pipeline[T]) RemoveMiddleware(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.middlewares, func(mw middleware[T]) bool { return mw.name == name })
+	if i < 0 {
+		return false
+	}
+	p.middlewares = slices.Delete(slices.Clone(p.middlewares), i, i+1)
+	p.compiled.Store(nil)
+	return true
+}
+
+// stages returns the current snapshot of registered raw steps. It must not be modified.
+func (p *Pipeline[T]) stages() []stage[T] {
+	if steps := p.steps.Load(); steps != nil {
+		return *steps
+	}
+	return nil
+}
+
+// Compile wraps every step in the registered middlewares and caches the result, which
+// the Execute methods reuse until a step or middleware is added or removed. They
+// compile on demand, so calling Compile only moves that cost out of the next
+// execution. Middleware state set up when wrapping, such as Memoize's cache, starts
+// afresh each time the pipeline is recompiled.
+func (p *Pipeline[T]) Compile() {
+	p.compiledStages()
+}
+
+// compiledStages returns the current snapshot of steps wrapped in middlewares,
+// compiling it if it is stale. It must not be modified.
+func (p *Pipeline[T]) compiledStages() []stage[T] {
+	if steps := p.compiled.Load(); steps != nil {
+		return *steps
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if steps := p.compiled.Load(); steps != nil {
+		return *steps
+	}
+	next := slices.Clone(p.stages())
+	for i := range next {
+		p.wrapStage(&next[i], i)
+	}
+	p.compiled.Store(&next)
+	return next
+}
+
+// WithRequireSteps makes the Execute methods fail with ErrEmptyPipeline when the pipeline
+// has no steps, instead of returning the input unchanged.
+func WithRequireSteps[T any]() Option[T] {
+	return func(p *Pipeline[T]) {
+		p.requireSteps = true
+	}
+}
+
+// IsEmpty reports whether the pipeline has no steps.
+func (p *Pipeline[T]) IsEmpty() bool {
+	return len(p.stages()) == 0
+}
+
+// Swap atomically replaces all steps with newSteps, named "step-N" by index.
+// Executions already in flight finish with the old steps; executions started afterwards
+// use the new ones, so steps can be reloaded at runtime without racing concurrent
+// Execute calls. The new steps get new StepInfo IDs and the old ones are retired.
+func (p *Pipeline[T]) Swap(newSteps []StepFunc[T]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	next := make([]stage[T], len(newSteps))
+	for i, step := range newSteps {
+		name := fmt.Sprintf("step-%d", i)
+		step, _ := timeBare(p.overheads, name, step, nil)
+		next[i] = stage[T]{id: stageIDs.Add(1), retired: make(chan struct{}), name: name, plain: step}
+	}
+	old := p.stages()
+	p.steps.Store(&next)
+	p.compiled.Store(nil)
+	for _, s := range old {
+		close(s.retired)
+	}
+}
+
 // Execute runs the pipeline on the given input, passing the output of each step to the next.
 // If any step returns an error, execution stops and that error is returned.
 func (p *Pipeline[T]) Execute(input T) (T, error) {
-	curr := input
-	var err error
-	for _, s := range p.steps {
-		curr, err = s(curr)
-		if err != nil {
-			return curr, err
-		}
-	}
-	return curr, nil
+	return p.ExecuteContext(context.Background(), input)
 }
 
 // Wrap converts a pure function f(T) T into a StepFunc[T], capturing no errors.
@@ -71,9 +305,48 @@ func Conditional[T any](predicate func(T) bool, thenStep, elseStep StepFunc[T])
 	}
 }
 
+// ConditionalThen creates a StepFunc that runs thenStep when predicate holds and
+// otherwise passes the input through unchanged.
+func ConditionalThen[T any](predicate func(T) bool, thenStep StepFunc[T]) StepFunc[T] {
+	return Conditional(predicate, thenStep, Identity[T]())
+}
+
+// Identity returns a StepFunc that passes its input through unchanged.
+func Identity[T any]() StepFunc[T] {
+	return func(input T) (T, error) {
+		return input, nil
+	}
+}
+
 // Parallel runs multiple StepFuncs on the same input concurrently, then combines their outputs.
+// A nil combiner returns the input unchanged once every step has succeeded, for fan-out
+// steps run purely for their side effects, such as sending several notifications.
+// With no steps, combiner is called with an empty slice, or the input is returned for
+// a nil combiner; use ParallelRequireSteps to treat that as a mistake instead.
 func Parallel[T any](combiner func([]T) (T, error), steps ...StepFunc[T]) StepFunc[T] {
+	run := ParallelResults(steps...)
 	return func(input T) (T, error) {
+		results, errs := run(input)
+		return combine(input, combiner, results, errs)
+	}
+}
+
+// ParallelRequireSteps is like Parallel but fails with ErrEmptyPipeline, returning the
+// input unchanged, when it is given no steps, for fan-outs whose steps come from
+// configuration and must not silently be empty.
+func ParallelRequireSteps[T any](combiner func([]T) (T, error), steps ...StepFunc[T]) StepFunc[T] {
+	if len(steps) == 0 {
+		return func(input T) (T, error) {
+			return input, ErrEmptyPipeline
+		}
+	}
+	return Parallel(combiner, steps...)
+}
+
+// ParallelResults runs multiple StepFuncs on the same input concurrently and returns each
+// step's output and error at the step's index, leaving merging to the caller.
+func ParallelResults[T any](steps ...StepFunc[T]) func(T) ([]T, []error) {
+	return func(input T) ([]T, []error) {
 		var (
 			wg      sync.WaitGroup
 			results = make([]T, len(steps))
@@ -87,13 +360,184 @@ func Parallel[T any](combiner func([]T) (T, error), steps ...StepFunc[T]) StepFu
 			}(i, step)
 		}
 		wg.Wait()
-		// Return first error if any
+		return results, errs
+	}
+}
+
+// ParallelNamed runs named StepFuncs on the same input concurrently, then combines their
+// outputs keyed by step name; a nil combiner returns the input. If any steps fail, the
+// input is returned along with the failures joined in name order, each prefixed by the
+// name of the step that produced it.
+func ParallelNamed[T any](combiner func(map[string]T) (T, error), steps map[string]StepFunc[T]) StepFunc[T] {
+	names := make([]string, 0, len(steps))
+	for name := range steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return func(input T) (T, error) {
+		var (
+			wg      sync.WaitGroup
+			results = make([]T, len(names))
+			errs    = make([]error, len(names))
+		)
+		wg.Add(len(names))
+		for i, name := range names {
+			go func(idx int, s StepFunc[T]) {
+				defer wg.Done()
+				results[idx], errs[idx] = s(input)
+			}(i, steps[name])
+		}
+		wg.Wait()
+		var failed []error
+		for i, err := range errs {
+			if err != nil {
+				failed = append(failed, fmt.Errorf("%s: %w", names[i], err))
+			}
+		}
+		if len(failed) > 0 {
+			return input, errors.Join(failed...)
+		}
+		if combiner == nil {
+			return input, nil
+		}
+		named := make(map[string]T, len(names))
+		for i, name := range names {
+			named[name] = results[i]
+		}
+		return combiner(named)
+	}
+}
+
+// SequentialParallel behaves like Parallel, including for a nil combiner, but runs the
+// steps one at a time in order. It produces the same combined output as Parallel,
+// trading concurrency for deterministic execution in tests and in single-threaded
+// environments such as WASM.
+func SequentialParallel[T any](combiner func([]T) (T, error), steps ...StepFunc[T]) StepFunc[T] {
+	return func(input T) (T, error) {
+		results := make([]T, len(steps))
+		errs := make([]error, len(steps))
+		for i, s := range steps {
+			results[i], errs[i] = s(input)
+		}
+		return combine(input, combiner, results, errs)
+	}
+}
+
+// MaybeParallel behaves like Parallel when parallel returns true and like
+// SequentialParallel otherwise, so concurrency can be turned off by a flag, for
+// debugging or on constrained hosts, without changing the pipeline. The flag is checked
+// on every call; both modes produce the same combined output.
+func MaybeParallel[T any](parallel func() bool, combiner func([]T) (T, error), steps ...StepFunc[T]) StepFunc[T] {
+	concurrent := Parallel(combiner, steps...)
+	sequential := SequentialParallel(combiner, steps...)
+	return func(input T) (T, error) {
+		if parallel() {
+			return concurrent(input)
+		}
+		return sequential(input)
+	}
+}
+
+// TwoPhase is a Parallel branch split into a side-effect-free Compute phase and a
+// Commit phase that applies its side effects to the computed value.
+type TwoPhase[T any] struct {
+	Compute StepFunc[T]
+	Commit  StepFunc[T]
+}
+
+// ParallelOrderedCommit runs the Compute phase of every branch on the same input
+// concurrently, then runs their Commit phases one at a time in registration order, so
+// side effects land in a deterministic order. If any Compute fails, nothing is
+// committed and the first error by position is returned; if a Commit fails, the later
+// ones are skipped. The committed outputs are combined as with Parallel, including for
+// a nil combiner.
+func ParallelOrderedCommit[T any](combiner func([]T) (T, error), steps ...TwoPhase[T]) StepFunc[T] {
+	computes := make([]StepFunc[T], len(steps))
+	for i, s := range steps {
+		computes[i] = s.Compute
+	}
+	run := ParallelResults(computes...)
+	return func(input T) (T, error) {
+		results, errs := run(input)
 		for _, err := range errs {
 			if err != nil {
-				return results[0], err
+				return input, err
 			}
 		}
-		// Combine results
-		return combiner(results)
+		for i, s := range steps {
+			out, err := s.Commit(results[i])
+			if err != nil {
+				return input, err
+			}
+			results[i] = out
+		}
+		return combine(input, combiner, results, nil)
+	}
+}
+
+// combine returns the first error in errs, or the combined results if every step succeeded.
+// A nil combiner yields input.
+func combine[T any](input T, combiner func([]T) (T, error), results []T, errs []error) (T, error) {
+	// Return first error if any
+	for _, err := range errs {
+		if err != nil {
+			return results[0], err
+		}
+	}
+	if combiner == nil {
+		return input, nil
+	}
+	// Combine results
+	return combiner(results)
+}
+
+// Isolated creates a StepFunc that runs step on a copy of the input produced by clone,
+// so the caller's value is never mutated. This matters for types holding pointers, maps,
+// or slices, especially inside Parallel where every branch shares the same input.
+// Go cannot deep-copy generically, so clone must be supplied by the caller.
+func Isolated[T any](clone func(T) T, step StepFunc[T]) StepFunc[T] {
+	return func(input T) (T, error) {
+		return step(clone(input))
+	}
+}
+
+// Catch creates a StepFunc that runs step and, if it fails, calls recover with the
+// original input and the error to produce a substitute value or a different error.
+func Catch[T any](step StepFunc[T], recover func(input T, err error) (T, error)) StepFunc[T] {
+	return func(input T) (T, error) {
+		out, err := step(input)
+		if err != nil {
+			return recover(input, err)
+		}
+		return out, nil
+	}
+}
+
+// Bracket creates a StepFunc that runs acquire, then use on its output, and always calls
+// release with the acquired value afterwards, whether use succeeds, fails or panics. It
+// scopes a resource (open, use, close) to a group of steps anywhere in a chain, unlike
+// pipeline-level finalizers. If acquire fails, neither use nor release runs.
+func Bracket[T any](acquire StepFunc[T], use StepFunc[T], release func(T)) StepFunc[T] {
+	return func(input T) (T, error) {
+		acquired, err := acquire(input)
+		if err != nil {
+			return acquired, err
+		}
+		defer release(acquired)
+		return use(acquired)
+	}
+}
+
+// Focus creates a StepFunc[T] that runs a StepFunc[F] on a part of T: get extracts the
+// part, step transforms it, and set writes the result back. This lets field-specific
+// steps, such as a StepFunc[string] normalizing an email address, be reused inside a
+// pipeline over a larger type. If step fails, the input is returned unchanged.
+func Focus[T, F any](get func(T) F, set func(T, F) T, step StepFunc[F]) StepFunc[T] {
+	return func(input T) (T, error) {
+		part, err := step(get(input))
+		if err != nil {
+			return input, err
+		}
+		return set(input, part), nil
 	}
 }
@@ -0,0 +1,42 @@
+// =====================
+// adapt_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Adapters(t *testing.T) {
+	handler := func(ctx context.Context, x int) (int, error) {
+		if ctx == nil {
+			return x, errors.New("nil context")
+		}
+		return x * 3, nil
+	}
+	positive := func(x int) error {
+		if x <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+	p := pipeline.New[int]().
+		Then(pipeline.FromValidator(positive)).
+		Then(pipeline.FromFunc2(handler))
+
+	if out, err := p.Execute(2); err != nil || out != 6 {
+		t.Errorf("Expected 6, got %d (%v)", out, err)
+	}
+	if out, err := p.Execute(-1); err == nil || out != -1 {
+		t.Errorf("Expected the validation error with the input, got %d (%v)", out, err)
+	}
+
+	back := pipeline.ToFunc2(pipeline.Wrap(func(x int) int { return x + 1 }))
+	if out, err := back(context.Background(), 1); err != nil || out != 2 {
+		t.Errorf("Expected 2, got %d (%v)", out, err)
+	}
+}
@@ -0,0 +1,61 @@
+// =====================
+// adaptive_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_AdaptiveParallel(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		limits []int
+	)
+	onLimit := func(n int) {
+		mu.Lock()
+		limits = append(limits, n)
+		mu.Unlock()
+	}
+	var fail bool
+	steps := make([]pipeline.StepFunc[int], 4)
+	for i := range steps {
+		steps[i] = func(x int) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fail {
+				return x, errors.New("overloaded")
+			}
+			return x, nil
+		}
+	}
+	step := pipeline.AdaptiveParallel(pipeline.SumCombiner[int], onLimit, steps...)
+	for i := 0; i < 3; i++ {
+		if out, err := step(1); err != nil || out != 4 {
+			t.Fatalf("Expected 4, got %d (%v)", out, err)
+		}
+	}
+	mu.Lock()
+	peak := 0
+	for _, n := range limits {
+		peak = max(peak, n)
+	}
+	fail = true
+	mu.Unlock()
+	if peak != 4 {
+		t.Errorf("Expected limit to grow to the number of steps, got peak %d (%v)", peak, limits)
+	}
+
+	if _, err := step(1); err == nil {
+		t.Fatalf("Expected an error from overloaded steps")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if last := limits[len(limits)-1]; last != 1 {
+		t.Errorf("Expected errors to back the limit off to 1, got %d (%v)", last, limits)
+	}
+}
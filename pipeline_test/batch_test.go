@@ -0,0 +1,97 @@
+// =====================
+// batch_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ExecuteBatchParallel(t *testing.T) {
+	p := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x * x }))
+	out, errs := p.ExecuteBatchParallel(context.Background(), []int{1, 2, 3, 4}, 2)
+	for i, want := range []int{1, 4, 9, 16} {
+		if errs[i] != nil || out[i] != want {
+			t.Errorf("Item %d: expected %d, got %d (%v)", i, want, out[i], errs[i])
+		}
+	}
+}
+
+func TestPipeline_ExecuteBatchParallelCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := pipeline.New[int]().ThenContext(func(ctx context.Context, x int) (int, error) {
+		if x == 2 {
+			cancel()
+		}
+		return x * 10, nil
+	})
+	inputs := make([]int, 100)
+	for i := range inputs {
+		inputs[i] = i
+	}
+	out, errs := p.ExecuteBatchParallel(ctx, inputs, 1)
+	for i := 0; i <= 2; i++ {
+		if errs[i] != nil || out[i] != i*10 {
+			t.Errorf("Item %d: expected completed result, got %d (%v)", i, out[i], errs[i])
+		}
+	}
+	for i := 3; i < len(inputs); i++ {
+		if !errors.Is(errs[i], context.Canceled) {
+			t.Fatalf("Item %d: expected context.Canceled, got %v", i, errs[i])
+		}
+	}
+}
+
+func TestPipeline_Batcher(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]int
+	)
+	flush := func(inputs []int) ([]int, error) {
+		mu.Lock()
+		batches = append(batches, inputs)
+		mu.Unlock()
+		out := make([]int, len(inputs))
+		for i, x := range inputs {
+			out[i] = x * 10
+		}
+		return out, nil
+	}
+	p := pipeline.New[int]().Then(pipeline.Batcher(3, 20*time.Millisecond, flush))
+
+	// Seven concurrent executions make two full batches and one flushed by maxWait.
+	outs, errs := p.ExecuteBatchParallel(context.Background(), []int{1, 2, 3, 4, 5, 6, 7}, 7)
+	for i, out := range outs {
+		if errs[i] != nil || out != (i+1)*10 {
+			t.Errorf("Input %d: expected %d, got %d (%v)", i+1, (i+1)*10, out, errs[i])
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	total := 0
+	for _, b := range batches {
+		total += len(b)
+	}
+	if len(batches) != 3 || total != 7 {
+		t.Errorf("Expected 7 inputs in 3 batches, got %v", batches)
+	}
+}
+
+func TestPipeline_BatcherErrors(t *testing.T) {
+	errFail := errors.New("backend down")
+	failing := pipeline.Batcher(2, time.Millisecond, func([]int) ([]int, error) { return nil, errFail })
+	if out, err := failing(5); err != errFail || out != 5 {
+		t.Errorf("Expected (5, %v), got (%d, %v)", errFail, out, err)
+	}
+	short := pipeline.Batcher(1, time.Millisecond, func([]int) ([]int, error) { return nil, nil })
+	if _, err := short(5); !errors.Is(err, pipeline.ErrBatchMismatch) {
+		t.Errorf("Expected ErrBatchMismatch when flush returns too few results, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// =====================
+// breaker_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_CircuitBreaker(t *testing.T) {
+	healthy := false
+	calls := 0
+	backend := func(x int) (int, error) {
+		calls++
+		if !healthy {
+			return x, errors.New("backend down")
+		}
+		return x * 2, nil
+	}
+	breaker := pipeline.NewCircuitBreaker(2, 20*time.Millisecond)
+	p := pipeline.New[int]().UseBreaker("backend", breaker).Then(backend)
+
+	p.Execute(1)
+	p.Execute(1)
+	if _, err := p.Execute(1); !errors.Is(err, pipeline.ErrCircuitOpen) || calls != 2 {
+		t.Fatalf("Expected the open circuit to reject the third call, got %v after %d calls", err, calls)
+	}
+	state := p.Health()["backend"]
+	if state.State != pipeline.CircuitOpen || state.Failures != 2 || state.ResetIn <= 0 {
+		t.Errorf("Unexpected open state %+v", state)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	healthy = true
+	if out, err := p.Execute(3); err != nil || out != 6 {
+		t.Fatalf("Expected the trial call to succeed, got %d (%v)", out, err)
+	}
+	if state := p.Health()["backend"]; state != (pipeline.BreakerState{State: pipeline.CircuitClosed}) {
+		t.Errorf("Expected the breaker to close after a successful trial, got %+v", state)
+	}
+}
+
+func TestPipeline_CircuitBreakerHalfOpenFailure(t *testing.T) {
+	breaker := pipeline.NewCircuitBreaker(1, 10*time.Millisecond)
+	step := pipeline.Breaker[int](breaker)(func(x int) (int, error) { return x, errors.New("still down") })
+
+	step(1)
+	time.Sleep(15 * time.Millisecond)
+	if _, err := step(1); err == nil || errors.Is(err, pipeline.ErrCircuitOpen) {
+		t.Fatalf("Expected the trial call to reach the step, got %v", err)
+	}
+	if state := breaker.State(); state.State != pipeline.CircuitOpen {
+		t.Errorf("Expected a failed trial to reopen the breaker, got %+v", state)
+	}
+	if h := pipeline.New[int]().Health(); len(h) != 0 {
+		t.Errorf("Expected no breakers, got %v", h)
+	}
+}
+
+func TestPipeline_CircuitBreakerTrialPanic(t *testing.T) {
+	breaker := pipeline.NewCircuitBreaker(1, 10*time.Millisecond)
+	calls := 0
+	p := pipeline.New[int]().
+		UseFactory(pipeline.Recover[int]()).
+		UseBreaker("backend", breaker).
+		Then(func(x int) (int, error) {
+			calls++
+			switch calls {
+			case 1:
+				return x, errors.New("backend down")
+			case 2:
+				panic("trial blew up")
+			}
+			return x * 2, nil
+		})
+
+	p.Execute(1)
+	time.Sleep(15 * time.Millisecond)
+	var panicErr pipeline.PanicError
+	if _, err := p.Execute(1); !errors.As(err, &panicErr) {
+		t.Fatalf("Expected the trial call to panic, got %v", err)
+	}
+	if state := breaker.State(); state.State != pipeline.CircuitOpen {
+		t.Fatalf("Expected a panicking trial to reopen the breaker, got %+v", state)
+	}
+	time.Sleep(15 * time.Millisecond)
+	if out, err := p.Execute(3); err != nil || out != 6 {
+		t.Errorf("Expected the next trial to be let through, got %d (%v)", out, err)
+	}
+}
@@ -0,0 +1,52 @@
+// =====================
+// broadcast_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Broadcast(t *testing.T) {
+	errNotify := errors.New("notify failed")
+	audit := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+	notify := pipeline.New[int]().Then(func(x int) (int, error) { return x, errNotify })
+	index := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x * 3 }))
+
+	results, errs := pipeline.Broadcast(audit, notify, index)(5)
+	if results[0] != 6 || results[2] != 15 {
+		t.Errorf("Expected [6 _ 15], got %v", results)
+	}
+	if errs[0] != nil || errs[1] != errNotify || errs[2] != nil {
+		t.Errorf("Expected only notify to fail, got %v", errs)
+	}
+}
+
+func TestPipeline_BroadcastLimit(t *testing.T) {
+	var running, peak atomic.Int32
+	track := func(x int) (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return x, nil
+	}
+	var pipelines []*pipeline.Pipeline[int]
+	for i := 0; i < 6; i++ {
+		pipelines = append(pipelines, pipeline.New[int]().Then(track))
+	}
+	pipeline.BroadcastLimit(2, pipelines...)(1)
+	if p := peak.Load(); p > 2 {
+		t.Errorf("Expected at most 2 concurrent pipelines, got %d", p)
+	}
+}
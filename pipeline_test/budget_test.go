@@ -0,0 +1,79 @@
+// =====================
+// budget_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Budget(t *testing.T) {
+	var calls []string
+	call := func(name string) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			calls = append(calls, name)
+			return x + 1, nil
+		}
+	}
+	flat := func(n int) func(int) int { return func(int) int { return n } }
+	p := pipeline.New[int](pipeline.WithBudget[int](10)).
+		ThenWithCost(flat(4), call("geo")).
+		Then(call("free")).
+		ThenWithCost(flat(5), call("pricing")).
+		ThenWithCost(flat(2), call("fraud"))
+
+	out, err := p.Execute(0)
+	if !errors.Is(err, pipeline.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+	}
+	if out != 3 || len(calls) != 3 {
+		t.Errorf("Expected three steps to run before the budget ran out, got %d after %v", out, calls)
+	}
+
+	unlimited := pipeline.New[int]().ThenWithCost(flat(1000), call("geo"))
+	if _, err := unlimited.Execute(0); err != nil {
+		t.Errorf("Expected no budget by default, got %v", err)
+	}
+}
+
+func TestPipeline_WithBudgetSplit(t *testing.T) {
+	var shares []time.Duration
+	record := func(ctx context.Context, x int) (int, error) {
+		deadline, _ := ctx.Deadline()
+		shares = append(shares, time.Until(deadline))
+		return x, nil
+	}
+	p := pipeline.New[int](pipeline.WithBudgetSplit[int](map[string]float64{"slow": 2})).
+		ThenContextNamed("slow", record).
+		ThenContextNamed("fast", record).
+		ThenContextNamed("other", record)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	if _, err := p.ExecuteContext(ctx, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if shares[0] < 180*time.Millisecond || shares[0] > 200*time.Millisecond {
+		t.Errorf("Expected the slow step to get half the budget, got %v", shares[0])
+	}
+	if shares[1] < 180*time.Millisecond || shares[1] > 200*time.Millisecond {
+		t.Errorf("Expected the fast step to get half of what was left, got %v", shares[1])
+	}
+
+	starved := pipeline.New[int](pipeline.WithBudgetSplit[int](nil)).
+		ThenContext(func(ctx context.Context, x int) (int, error) {
+			<-ctx.Done()
+			return x, ctx.Err()
+		}).
+		ThenContext(record)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := starved.ExecuteContext(ctx, 1); !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+		t.Errorf("Expected the first step to run out of its own share only, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// =====================
+// builder_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func newTestBuilder() *pipeline.Builder[int] {
+	return pipeline.NewBuilder[int]().
+		RegisterStep("add", func(params map[string]any) (pipeline.StepFunc[int], error) {
+			n, ok := params["n"].(float64)
+			if !ok {
+				return nil, errors.New("add: missing n")
+			}
+			return pipeline.Wrap(func(x int) int { return x + int(n) }), nil
+		}).
+		RegisterStep("double", func(map[string]any) (pipeline.StepFunc[int], error) {
+			return pipeline.Wrap(func(x int) int { return x * 2 }), nil
+		})
+}
+
+func TestPipeline_BuildFromSpec(t *testing.T) {
+	var spec []pipeline.StepSpec
+	config := `[{"name": "add", "params": {"n": 3}}, {"name": "double"}]`
+	if err := json.Unmarshal([]byte(config), &spec); err != nil {
+		t.Fatalf("Unexpected error decoding spec: %v", err)
+	}
+	p, err := newTestBuilder().BuildFromSpec(spec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out, _ := p.Execute(1); out != 8 {
+		t.Errorf("Expected 8, got %d", out)
+	}
+	if got := p.String(); got != "Pipeline[2 steps: add, double]" {
+		t.Errorf("Expected steps named after their factories, got %s", got)
+	}
+}
+
+func TestPipeline_BuildFromSpecErrors(t *testing.T) {
+	b := newTestBuilder()
+	if _, err := b.BuildFromSpec([]pipeline.StepSpec{{Name: "triple"}}); !errors.Is(err, pipeline.ErrUnknownStep) {
+		t.Errorf("Expected ErrUnknownStep, got %v", err)
+	}
+	if _, err := b.BuildFromSpec([]pipeline.StepSpec{{Name: "double"}, {Name: "add"}}); err == nil || err.Error() != "step 1 (add): add: missing n" {
+		t.Errorf("Expected the factory error with its position, got %v", err)
+	}
+}
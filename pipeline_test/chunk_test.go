@@ -0,0 +1,86 @@
+// =====================
+// chunk_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Chunk(t *testing.T) {
+	var running, peak atomic.Int32
+	upper := func(s string) (string, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return strings.ToUpper(s), nil
+	}
+	split := func(doc string) []string { return strings.Split(doc, "\n") }
+	join := func(_ string, paras []string) string { return strings.Join(paras, "\n") }
+
+	step := pipeline.Chunk(split, upper, join, 2)
+	out, err := pipeline.New[string]().Then(step).Execute("a\nb\nc\nd\ne")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != "A\nB\nC\nD\nE" {
+		t.Errorf("Expected upper-cased paragraphs in order, got %q", out)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("Expected at most 2 concurrent elements, got %d", p)
+	}
+
+	errEmpty := errors.New("empty paragraph")
+	strict := pipeline.Chunk(split, func(s string) (string, error) {
+		if s == "" {
+			return s, errEmpty
+		}
+		return s, nil
+	}, join, 3)
+	if out, err := strict("a\n\nb"); err != errEmpty || out != "a\n\nb" {
+		t.Errorf("Expected %v with original input, got %q (%v)", errEmpty, out, err)
+	}
+}
+
+func TestPipeline_MapSlice(t *testing.T) {
+	var calls int
+	parse := func(s string) (string, error) {
+		calls++
+		if s == "" {
+			return s, errors.New("empty record")
+		}
+		return strings.ToUpper(s), nil
+	}
+
+	out, err := pipeline.New[[]string]().Then(pipeline.MapSlice(parse)).Execute([]string{"a", "b", "c"})
+	if err != nil || strings.Join(out, ",") != "A,B,C" {
+		t.Fatalf("Expected [A B C], got %v (%v)", out, err)
+	}
+
+	calls = 0
+	input := []string{"a", "", "c", ""}
+	out, err = pipeline.MapSlice(parse)(input)
+	if err == nil || err.Error() != "element 1: empty record\nelement 3: empty record" || calls != 4 {
+		t.Errorf("Expected every failure collected after %d calls, got %v", calls, err)
+	}
+	if strings.Join(out, ",") != "a,,c," {
+		t.Errorf("Expected the input back on failure, got %v", out)
+	}
+
+	calls = 0
+	if _, err := pipeline.MapSliceFailFast(parse)(input); err == nil || err.Error() != "element 1: empty record" || calls != 2 {
+		t.Errorf("Expected to stop at the first failure after 2 calls, got %v after %d", err, calls)
+	}
+}
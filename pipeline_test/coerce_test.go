@@ -0,0 +1,53 @@
+// =====================
+// coerce_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_CoerceNumbers(t *testing.T) {
+	toInt := pipeline.Coerce[int]()
+	for _, v := range []any{3, int8(3), uint16(3), 3.0, json.Number("3")} {
+		if out, err := toInt(v); err != nil || out != 3 {
+			t.Errorf("Expected %T(%v) to coerce to 3, got %d (%v)", v, v, out, err)
+		}
+	}
+	for _, v := range []any{3.5, "3", nil} {
+		if _, err := toInt(v); !errors.Is(err, pipeline.ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch for %T(%v), got %v", v, v, err)
+		}
+	}
+	if _, err := pipeline.Coerce[uint8]()(300); !errors.Is(err, pipeline.ErrTypeMismatch) {
+		t.Errorf("Expected overflow to fail, got %v", err)
+	}
+	if _, err := pipeline.Coerce[uint]()(-1); !errors.Is(err, pipeline.ErrTypeMismatch) {
+		t.Errorf("Expected a negative value to fail for uint, got %v", err)
+	}
+	if out, err := pipeline.Coerce[float64]()(int32(7)); err != nil || out != 7 {
+		t.Errorf("Expected 7, got %v (%v)", out, err)
+	}
+}
+
+func TestPipeline_CoerceJSON(t *testing.T) {
+	type order struct {
+		ID    int      `json:"id"`
+		Items []string `json:"items"`
+	}
+	in := map[string]any{"id": 7.0, "items": []any{"a", "b"}}
+	out, err := pipeline.Coerce[order]()(in)
+	if err != nil || out.ID != 7 || len(out.Items) != 2 {
+		t.Errorf("Expected the map to fill the struct, got %+v (%v)", out, err)
+	}
+
+	var p *pipeline.AnyPipeline = pipeline.New[any]()
+	p.Then(func(v any) (any, error) { return pipeline.Coerce[[]int]()(v) })
+	if got, err := p.Execute([]any{1.0, 2.0}); err != nil || len(got.([]int)) != 2 {
+		t.Errorf("Expected []int, got %v (%v)", got, err)
+	}
+}
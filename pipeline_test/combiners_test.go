@@ -0,0 +1,39 @@
+// =====================
+// combiners_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Combiners(t *testing.T) {
+	f1 := pipeline.Wrap(func(x int) int { return x + 1 })
+	f2 := pipeline.Wrap(func(x int) int { return x * 2 })
+
+	sum, err := pipeline.New[int]().Then(pipeline.Parallel(pipeline.SumCombiner[int], f1, f2)).Execute(3)
+	if err != nil || sum != 10 {
+		t.Errorf("Expected 10, got %d (%v)", sum, err)
+	}
+
+	first, _ := pipeline.FirstNonZeroCombiner([]string{"", "b", "c"})
+	if first != "b" {
+		t.Errorf("Expected b, got %q", first)
+	}
+
+	maxOf := pipeline.MergeCombiner(func(a, b int) int {
+		if b > a {
+			return b
+		}
+		return a
+	})
+	largest, _ := pipeline.New[int]().Then(pipeline.Parallel(maxOf, f1, f2)).Execute(3)
+	if largest != 6 {
+		t.Errorf("Expected 6, got %d", largest)
+	}
+	if empty, _ := maxOf(nil); empty != 0 {
+		t.Errorf("Expected zero value for no results, got %d", empty)
+	}
+}
@@ -0,0 +1,58 @@
+// =====================
+// compose_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func tracing(name string, trace *[]string) pipeline.Middleware[int] {
+	return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			*trace = append(*trace, name+">")
+			out, err := next(x)
+			*trace = append(*trace, "<"+name)
+			return out, err
+		}
+	}
+}
+
+func TestPipeline_ThenPipeline(t *testing.T) {
+	for _, applyOuter := range []bool{true, false} {
+		var trace []string
+		sub := pipeline.New[int]().
+			Use(tracing("I1", &trace)).
+			Use(tracing("I2", &trace)).
+			Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+			Then(pipeline.Wrap(func(x int) int { return x * 10 }))
+		outer := pipeline.New[int]().
+			Use(tracing("O1", &trace)).
+			Use(tracing("O2", &trace)).
+			ThenPipeline(sub, applyOuter)
+
+		out, err := outer.Execute(1)
+		if err != nil || out != 20 {
+			t.Fatalf("Expected 20, got %d (%v)", out, err)
+		}
+		inner := "I1> I2> <I2 <I1 I1> I2> <I2 <I1"
+		want := "[" + inner + "]"
+		if applyOuter {
+			want = "[O1> O2> " + inner + " <O2 <O1]"
+		}
+		if got := fmt.Sprint(trace); got != want {
+			t.Errorf("applyOuter=%v: expected %s, got %s", applyOuter, want, got)
+		}
+	}
+}
+
+func TestPipeline_AsStep(t *testing.T) {
+	sub := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x * 2 }))
+	p := pipeline.New[int]().ThenContext(sub.AsStep()).ThenContext(sub.AsStep())
+	if out, err := p.Execute(3); err != nil || out != 12 {
+		t.Errorf("Expected 12, got %d (%v)", out, err)
+	}
+}
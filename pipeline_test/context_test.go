@@ -0,0 +1,179 @@
+// =====================
+// context_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func sumCombiner(results []int) (int, error) {
+	sum := 0
+	for _, v := range results {
+		sum += v
+	}
+	return sum, nil
+}
+
+func TestPipeline_ParallelContextDeadlinePropagation(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	branch := func(ctx context.Context, x int) (int, error) {
+		d, ok := ctx.Deadline()
+		if !ok || !d.Equal(deadline) {
+			return x, errors.New("branch did not see the parent deadline")
+		}
+		return x, nil
+	}
+	step := pipeline.ParallelContext(sumCombiner, branch, branch, branch)
+	out, err := pipeline.New[int]().ThenContext(step).ExecuteContext(ctx, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 6 {
+		t.Errorf("Expected 6, got %d", out)
+	}
+}
+
+func TestPipeline_ParallelContextDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	cancelled := make(chan struct{}, 2)
+	slow := func(ctx context.Context, x int) (int, error) {
+		select {
+		case <-ctx.Done():
+			cancelled <- struct{}{}
+			return x, ctx.Err()
+		case <-time.After(time.Minute):
+			return x, nil
+		}
+	}
+	step := pipeline.ParallelContext(sumCombiner, slow, slow)
+	_, err := step(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(cancelled) != 2 {
+		t.Errorf("Expected both branches to be cancelled, got %d", len(cancelled))
+	}
+}
+
+func TestPipeline_ParallelContextCancelsOnError(t *testing.T) {
+	errFail := errors.New("failure")
+	failing := func(ctx context.Context, x int) (int, error) { return x, errFail }
+	waiting := func(ctx context.Context, x int) (int, error) {
+		<-ctx.Done()
+		return x, ctx.Err()
+	}
+	_, err := pipeline.ParallelContext(sumCombiner, waiting, failing)(context.Background(), 1)
+	if err != errFail {
+		t.Errorf("Expected %v, got %v", errFail, err)
+	}
+}
+
+func TestPipeline_Speculative(t *testing.T) {
+	cancelled := make(chan string, 2)
+	branch := func(name string, delta int) pipeline.StepFuncContext[int] {
+		return func(ctx context.Context, x int) (int, error) {
+			select {
+			case <-ctx.Done():
+				cancelled <- name
+				return 0, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return x + delta, nil
+			}
+		}
+	}
+	predicate := func(x int) bool {
+		time.Sleep(40 * time.Millisecond)
+		return x > 0
+	}
+	step := pipeline.Speculative(predicate, branch("then", 1), branch("else", -1))
+
+	start := time.Now()
+	out, err := step(context.Background(), 5)
+	if err != nil || out != 6 {
+		t.Fatalf("Expected 6 from the then branch, got %d (%v)", out, err)
+	}
+	if elapsed := time.Since(start); elapsed > 80*time.Millisecond {
+		t.Errorf("Expected the branch to overlap the predicate, took %v", elapsed)
+	}
+	select {
+	case name := <-cancelled:
+		if name != "else" {
+			t.Errorf("Expected the else branch to be cancelled, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the discarded branch to be cancelled")
+	}
+
+	if out, _ := step(context.Background(), -5); out != -6 {
+		t.Errorf("Expected -6 from the else branch, got %d", out)
+	}
+}
+
+func TestPipeline_ParallelCollect(t *testing.T) {
+	type order struct {
+		ID    string
+		Notes string
+	}
+	pricing := func(ctx context.Context, o order, c *pipeline.Collector[string, any]) (order, error) {
+		c.Set("price", 42.5)
+		return o, nil
+	}
+	stock := func(ctx context.Context, o order, c *pipeline.Collector[string, any]) (order, error) {
+		c.Set("in_stock", true)
+		return o, nil
+	}
+	combiner := func(results []order, c *pipeline.Collector[string, any]) (order, error) {
+		price, _ := c.Get("price")
+		inStock, _ := c.Get("in_stock")
+		o := results[0]
+		o.Notes = fmt.Sprintf("%v/%v", price, inStock)
+		return o, nil
+	}
+	step := pipeline.ParallelCollect(combiner, pricing, stock)
+
+	out, err := step(context.Background(), order{ID: "o-1"})
+	if err != nil || out.Notes != "42.5/true" {
+		t.Errorf("Expected notes merged from side outputs, got %+v (%v)", out, err)
+	}
+
+	errFail := errors.New("failure")
+	failing := func(ctx context.Context, o order, c *pipeline.Collector[string, any]) (order, error) {
+		return o, errFail
+	}
+	if _, err := pipeline.ParallelCollect(combiner, pricing, failing)(context.Background(), order{}); err != errFail {
+		t.Errorf("Expected %v, got %v", errFail, err)
+	}
+}
+
+func TestPipeline_Heartbeat(t *testing.T) {
+	var beats atomic.Int32
+	p := pipeline.New[int]().
+		UseContext(pipeline.Heartbeat[int](10*time.Millisecond, func() { beats.Add(1) })).
+		Then(func(x int) (int, error) {
+			time.Sleep(55 * time.Millisecond)
+			return x, nil
+		})
+
+	if _, err := p.Execute(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	n := beats.Load()
+	if n < 3 || n > 6 {
+		t.Errorf("Expected about five beats during the step, got %d", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if beats.Load() != n {
+		t.Error("Expected beating to stop once the step returned")
+	}
+}
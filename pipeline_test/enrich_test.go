@@ -0,0 +1,202 @@
+// =====================
+// enrich_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Timeout(t *testing.T) {
+	slow := func(x int) (int, error) {
+		time.Sleep(time.Second)
+		return x, nil
+	}
+	_, err := pipeline.New[int]().Use(pipeline.Timeout[int](10 * time.Millisecond)).Then(slow).Execute(1)
+	if !errors.Is(err, pipeline.ErrStepTimeout) {
+		t.Errorf("Expected ErrStepTimeout, got %v", err)
+	}
+
+	waiting := func(ctx context.Context, x int) (int, error) {
+		<-ctx.Done()
+		return x, ctx.Err()
+	}
+	_, err = pipeline.New[int]().
+		UseContext(pipeline.TimeoutContext[int](10 * time.Millisecond)).
+		ThenContext(waiting).
+		Execute(1)
+	if !errors.Is(err, pipeline.ErrStepTimeout) {
+		t.Errorf("Expected ErrStepTimeout, got %v", err)
+	}
+}
+
+func TestPipeline_ExecuteWithTimeout(t *testing.T) {
+	stopped := make(chan struct{})
+	slow := func(ctx context.Context, x int) (int, error) {
+		<-ctx.Done()
+		close(stopped)
+		return x, ctx.Err()
+	}
+	p := pipeline.New[int]().ThenContext(slow)
+	cached := func(x int) (int, error) { return -x, nil }
+
+	out, err := p.ExecuteWithTimeout(context.Background(), 10*time.Millisecond, 7, cached)
+	if err != nil || out != -7 {
+		t.Errorf("Expected the fallback result -7, got %d (%v)", out, err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the abandoned execution to be cancelled")
+	}
+
+	fast := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x * 2 }))
+	if out, err := fast.ExecuteWithTimeout(context.Background(), time.Second, 7, cached); err != nil || out != 14 {
+		t.Errorf("Expected 14 within the deadline, got %d (%v)", out, err)
+	}
+	if _, err := pipeline.New[int]().ThenContext(func(ctx context.Context, x int) (int, error) {
+		<-ctx.Done()
+		return x, ctx.Err()
+	}).ExecuteWithTimeout(context.Background(), 10*time.Millisecond, 7, nil); !errors.Is(err, pipeline.ErrDeadlineExceeded) {
+		t.Errorf("Expected ErrDeadlineExceeded without a fallback, got %v", err)
+	}
+}
+
+func TestPipeline_ThenDeadline(t *testing.T) {
+	cancelled := make(chan struct{})
+	p := pipeline.New[int](pipeline.WithErrorStats[int]()).
+		ThenDeadline("fast", time.Second, pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenDeadlineContext("enrich", 10*time.Millisecond, func(ctx context.Context, x int) (int, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return x, ctx.Err()
+		})
+
+	out, err := p.Execute(1)
+	if !errors.Is(err, pipeline.ErrStepTimeout) || out != 2 {
+		t.Errorf("Expected ErrStepTimeout after the fast step, got %d (%v)", out, err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Errorf("Expected the context-aware step to be cancelled")
+	}
+	if stats := p.ErrorStats(); stats["enrich"] != 1 || stats["fast"] != 0 {
+		t.Errorf("Expected the timeout recorded against enrich, got %v", stats)
+	}
+
+	slow := pipeline.New[int]().ThenDeadline("slow", 10*time.Millisecond, func(x int) (int, error) {
+		time.Sleep(time.Second)
+		return x, nil
+	})
+	if _, err := slow.Execute(1); !errors.Is(err, pipeline.ErrStepTimeout) {
+		t.Errorf("Expected ErrStepTimeout for a plain step, got %v", err)
+	}
+}
+
+func TestPipeline_Memoize(t *testing.T) {
+	var calls atomic.Int32
+	square := func(x int) (int, error) {
+		calls.Add(1)
+		return x * x, nil
+	}
+	p := pipeline.New[int]().Use(pipeline.Memoize[int](time.Minute)).Then(square)
+	for i := 0; i < 3; i++ {
+		if out, _ := p.Execute(4); out != 16 {
+			t.Fatalf("Expected 16, got %d", out)
+		}
+	}
+	p.Execute(5)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected 2 calls, got %d", n)
+	}
+}
+
+func TestPipeline_Enrich(t *testing.T) {
+	var calls atomic.Int32
+	lookup := func(x int) (int, error) {
+		if calls.Add(1) == 1 {
+			return x, errors.New("transient")
+		}
+		return x + 100, nil
+	}
+	step := pipeline.Enrich(lookup,
+		pipeline.EnrichRetry(3, nil),
+		pipeline.EnrichCache(time.Minute),
+		pipeline.EnrichTimeout(time.Second),
+	)
+	p := pipeline.New[int]().Then(step)
+	for i := 0; i < 2; i++ {
+		out, err := p.Execute(1)
+		if err != nil || out != 101 {
+			t.Fatalf("Expected 101, got %d (%v)", out, err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected one failed and one cached call, got %d calls", n)
+	}
+}
+
+func TestPipeline_MemoizeInvalidate(t *testing.T) {
+	var calls atomic.Int32
+	square := func(x int) (int, error) {
+		calls.Add(1)
+		return x * x, nil
+	}
+	memo := pipeline.NewMemoize[int](time.Minute, nil)
+	p := pipeline.New[int]().UseFactory(memo.Middleware()).Then(square)
+
+	p.Execute(4)
+	p.Execute(4)
+	p.Execute(5)
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("Expected 2 calls, got %d", n)
+	}
+	memo.Invalidate(4)
+	p.Execute(4)
+	p.Execute(5)
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected only the invalidated input to be recomputed, got %d calls", n)
+	}
+	memo.Clear()
+	p.Execute(5)
+	if n := calls.Load(); n != 4 {
+		t.Errorf("Expected Clear to evict everything, got %d calls", n)
+	}
+}
+
+func TestPipeline_MemoizeDuplicateNames(t *testing.T) {
+	memo := pipeline.NewMemoize[int](time.Minute, nil)
+	p := pipeline.New[int]().
+		UseFactory(memo.Middleware()).
+		ThenNamed("x", pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenNamed("x", pipeline.Wrap(func(x int) int { return x * 10 }))
+
+	if out, _ := p.Execute(1); out != 20 {
+		t.Fatalf("Expected 20, got %d", out)
+	}
+	if out, _ := p.Execute(2); out != 30 {
+		t.Errorf("Expected steps with the same name to keep separate caches, got %d", out)
+	}
+}
+
+func TestPipeline_MemoizeSwap(t *testing.T) {
+	memo := pipeline.NewMemoize[int](time.Minute, nil)
+	p := pipeline.New[int]().
+		UseFactory(memo.Middleware()).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+
+	if out, _ := p.Execute(1); out != 2 {
+		t.Fatalf("Expected 2, got %d", out)
+	}
+	p.Swap([]pipeline.StepFunc[int]{pipeline.Wrap(func(x int) int { return x * 100 })})
+	if out, _ := p.Execute(1); out != 100 {
+		t.Errorf("Expected the swapped-in step not to see the old cache, got %d", out)
+	}
+}
@@ -0,0 +1,135 @@
+// =====================
+// errors_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_SentinelErrors(t *testing.T) {
+	id := pipeline.Identity[int]()
+	slow := func(x int) (int, error) {
+		time.Sleep(50 * time.Millisecond)
+		return x, nil
+	}
+	fail := func(x int) (int, error) { return x, errors.New("fail") }
+	stopped := pipeline.New[int]()
+	stopped.Stop(context.Background())
+
+	cases := map[error]func() error{
+		pipeline.ErrStepTimeout: func() error {
+			_, err := pipeline.Timeout[int](time.Millisecond)(slow)(1)
+			return err
+		},
+		pipeline.ErrCircuitOpen: func() error {
+			step := pipeline.Breaker[int](pipeline.NewCircuitBreaker(1, time.Minute))(fail)
+			step(1)
+			_, err := step(1)
+			return err
+		},
+		pipeline.ErrRateLimited: func() error {
+			var step pipeline.StepFunc[int]
+			step = pipeline.LimitConcurrencyFailFast[int](1)(func(x int) (int, error) {
+				if x == 0 {
+					return step(1)
+				}
+				return x, nil
+			})
+			_, err := step(0)
+			return err
+		},
+		pipeline.ErrMaxIterations: func() error {
+			_, err := pipeline.RetryUntil[int](2, nil, func(int) bool { return false })(id)(1)
+			return err
+		},
+		pipeline.ErrBudgetExceeded: func() error {
+			_, err := pipeline.New[int](pipeline.WithBudget[int](1)).ThenWithCost(func(int) int { return 2 }, id).Execute(1)
+			return err
+		},
+		pipeline.ErrDeadlineExceeded: func() error {
+			_, err := pipeline.New[int]().Then(slow).ExecuteWithTimeout(context.Background(), time.Millisecond, 1, nil)
+			return err
+		},
+		pipeline.ErrEmptyPipeline: func() error {
+			_, err := pipeline.New[int](pipeline.WithRequireSteps[int]()).Execute(1)
+			return err
+		},
+		pipeline.ErrTooLarge: func() error {
+			_, err := pipeline.MaxSize[int](func(x int) int { return x }, 1)(id)(2)
+			return err
+		},
+		pipeline.ErrPipelineStopped: func() error {
+			return stopped.Start()
+		},
+		pipeline.ErrNoRoute: func() error {
+			_, err := pipeline.WeightedRouter[int](nil, nil)(1)
+			return err
+		},
+		pipeline.ErrQuorumTooLarge: func() error {
+			_, err := pipeline.Quorum[int](2, nil)(context.Background(), 1)
+			return err
+		},
+		pipeline.ErrUnknownStep: func() error {
+			_, err := pipeline.NewBuilder[int]().BuildFromSpec([]pipeline.StepSpec{{Name: "missing"}})
+			return err
+		},
+		pipeline.ErrBatchMismatch: func() error {
+			_, err := pipeline.Batcher(1, time.Millisecond, func([]int) ([]int, error) { return nil, nil })(1)
+			return err
+		},
+		pipeline.ErrQueueFull: func() error {
+			p := pipeline.New[int](pipeline.WithQueue[int](1, false, nil))
+			p.Enqueue(1)
+			return p.Enqueue(2)
+		},
+		pipeline.ErrNoQueue: func() error {
+			return pipeline.New[int]().Enqueue(1)
+		},
+		pipeline.ErrTypeMismatch: func() error {
+			_, err := pipeline.Coerce[int]()("one")
+			return err
+		},
+		pipeline.ErrInvalidInput: func() error {
+			_, err := pipeline.New[int](pipeline.WithInputSchema(func(int) error { return errors.New("bad") })).Then(id).Execute(1)
+			return err
+		},
+		pipeline.ErrInvalidOutput: func() error {
+			_, err := pipeline.New[int](pipeline.WithOutputSchema(func(int) error { return errors.New("bad") })).Then(id).Execute(1)
+			return err
+		},
+	}
+	for sentinel, produce := range cases {
+		if err := produce(); !errors.Is(err, sentinel) {
+			t.Errorf("Expected %v from its documented producer, got %v", sentinel, err)
+		}
+	}
+}
+
+func TestPipeline_RichErrors(t *testing.T) {
+	p := pipeline.New[int](pipeline.WithRichErrors[int]()).
+		Then(pipeline.Wrap(func(x int) int { return x * 2 })).
+		ThenNamed("check", pipeline.MaxSize[int](func(x int) int { return x }, 5)(pipeline.Identity[int]()))
+
+	_, err := p.Execute(4)
+	var execErr pipeline.ExecutionError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("Expected an ExecutionError, got %v", err)
+	}
+	if execErr.Step != "check" || execErr.Index != 1 || execErr.Input != 8 || execErr.Elapsed <= 0 {
+		t.Errorf("Unexpected error details %+v", execErr)
+	}
+	if !errors.Is(err, pipeline.ErrTooLarge) {
+		t.Errorf("Expected errors.Is to match the underlying sentinel, got %v", err)
+	}
+
+	_, err = pipeline.New[int]().Then(pipeline.MaxSize[int](func(x int) int { return x }, 5)(pipeline.Identity[int]())).Execute(8)
+	if errors.As(err, &execErr) {
+		t.Errorf("Expected a plain error without WithRichErrors, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// =====================
+// events_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_EventSink(t *testing.T) {
+	events := make(chan pipeline.StepEvent, 10)
+	errFail := errors.New("failure")
+	var seenID string
+	p := pipeline.New[int](pipeline.WithEventSink[int](pipeline.ChannelSink(events))).
+		ThenNamed("parse", pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenContextNamed("save", func(ctx context.Context, x int) (int, error) {
+			seenID = pipeline.ExecutionID(ctx)
+			return x, errFail
+		})
+
+	if _, err := p.Execute(1); err != errFail {
+		t.Fatalf("Expected %v, got %v", errFail, err)
+	}
+	close(events)
+	var got []pipeline.StepEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	expected := []struct {
+		step  string
+		phase pipeline.Phase
+	}{
+		{"parse", pipeline.PhaseStart},
+		{"parse", pipeline.PhaseEnd},
+		{"save", pipeline.PhaseStart},
+		{"save", pipeline.PhaseError},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), got)
+	}
+	for i, e := range got {
+		if e.Step != expected[i].step || e.Phase != expected[i].phase {
+			t.Errorf("Event %d: expected %s/%s, got %s/%s", i, expected[i].step, expected[i].phase, e.Step, e.Phase)
+		}
+		if e.ExecutionID == "" || e.ExecutionID != seenID {
+			t.Errorf("Event %d: expected execution id %q, got %q", i, seenID, e.ExecutionID)
+		}
+	}
+	if got[3].Err != errFail || got[3].Index != 1 {
+		t.Errorf("Expected error event for step 1, got %+v", got[3])
+	}
+}
+
+func TestPipeline_ExecutionIDFromContext(t *testing.T) {
+	events := make(chan pipeline.StepEvent, 2)
+	p := pipeline.New[int](pipeline.WithEventSink[int](pipeline.ChannelSink(events))).
+		Then(pipeline.Identity[int]())
+	p.ExecuteContext(pipeline.ContextWithExecutionID(context.Background(), "req-42"), 1)
+	if e := <-events; e.ExecutionID != "req-42" {
+		t.Errorf("Expected caller-supplied execution id, got %q", e.ExecutionID)
+	}
+}
+
+func TestPipeline_WithSummary(t *testing.T) {
+	var summaries []pipeline.ExecutionSummary
+	boom := errors.New("boom")
+	p := pipeline.New[int](pipeline.WithSummary[int](func(s pipeline.ExecutionSummary) {
+		summaries = append(summaries, s)
+	})).
+		ThenNamed("sleep", func(x int) (int, error) {
+			time.Sleep(5 * time.Millisecond)
+			return x, nil
+		}).
+		ThenNamed("check", func(x int) (int, error) {
+			if x < 0 {
+				return x, boom
+			}
+			return x, nil
+		})
+
+	p.Execute(1)
+	p.ExecuteContext(pipeline.ContextWithExecutionID(context.Background(), "req-2"), -1)
+	if len(summaries) != 2 {
+		t.Fatalf("Expected one summary per execution, got %d", len(summaries))
+	}
+	ok, failed := summaries[0], summaries[1]
+	if ok.Err != nil || ok.FailedStep != "" || len(ok.Steps) != 2 || ok.ExecutionID == "" {
+		t.Errorf("Unexpected summary of a successful execution: %+v", ok)
+	}
+	if ok.Steps[0].Name != "sleep" || ok.Steps[0].Duration < 5*time.Millisecond || ok.Duration < ok.Steps[0].Duration {
+		t.Errorf("Unexpected step timings: %+v", ok)
+	}
+	if !errors.Is(failed.Err, boom) || failed.FailedStep != "check" || failed.ExecutionID != "req-2" {
+		t.Errorf("Unexpected summary of a failed execution: %+v", failed)
+	}
+}
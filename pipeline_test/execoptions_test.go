@@ -0,0 +1,60 @@
+// =====================
+// execoptions_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ExecuteWithOptions(t *testing.T) {
+	var seenID string
+	p := pipeline.New[int]().
+		ThenContext(func(ctx context.Context, x int) (int, error) {
+			seenID = pipeline.ExecutionID(ctx)
+			return x + 1, nil
+		}).
+		ThenContext(func(ctx context.Context, x int) (int, error) {
+			if x > 10 {
+				<-ctx.Done()
+				return x, ctx.Err()
+			}
+			return x, nil
+		})
+
+	events := make(chan pipeline.StepEvent, 10)
+	out, err := p.ExecuteWithOptions(1, pipeline.ExecID("req-42"), pipeline.ExecTrace(pipeline.ChannelSink(events)))
+	if err != nil || out != 2 {
+		t.Fatalf("Expected 2, got %d (%v)", out, err)
+	}
+	if seenID != "req-42" {
+		t.Errorf("Expected execution id req-42, got %q", seenID)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 traced events, got %d", len(events))
+	}
+	if e := <-events; e.ExecutionID != "req-42" || e.Step != "step-0" || e.Phase != pipeline.PhaseStart {
+		t.Errorf("Unexpected first event %+v", e)
+	}
+
+	// Options apply to their own call only.
+	seenID = ""
+	p.ExecuteWithOptions(1)
+	if seenID != "" || len(events) != 3 {
+		t.Errorf("Expected no id or events without options, got %q and %d events", seenID, len(events))
+	}
+
+	if _, err := p.ExecuteWithOptions(20, pipeline.ExecTimeout(10*time.Millisecond)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the per-call timeout to apply, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.ExecuteWithOptions(1, pipeline.ExecContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the given context to be used, got %v", err)
+	}
+}
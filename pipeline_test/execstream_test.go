@@ -0,0 +1,52 @@
+// =====================
+// execstream_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ExecuteStream(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	p := pipeline.New[int]().Then(func(x int) (int, error) {
+		if x == 0 {
+			started <- struct{}{}
+			<-release
+		}
+		if x%2 == 1 {
+			return x, errors.New("odd")
+		}
+		return x, nil
+	})
+
+	in := make(chan int, 10)
+	for i := range 5 {
+		in <- i
+	}
+	close(in)
+	stream := p.ExecuteStream(context.Background(), in, 1)
+
+	<-started
+	if stats := stream.StreamStats(); stats.InFlight != 1 || stats.Backlog != 4 || stats.Processed != 0 {
+		t.Errorf("Expected 1 in flight and 4 buffered, got %+v", stats)
+	}
+	close(release)
+
+	count := 0
+	for range stream.Results() {
+		count++
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 results, got %d", count)
+	}
+	expected := pipeline.StreamStats{Processed: 5, Errors: 2}
+	if stats := stream.StreamStats(); stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+}
@@ -0,0 +1,74 @@
+// =====================
+// factory_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// fakeStatsD records the metric names it receives.
+type fakeStatsD struct {
+	mu     sync.Mutex
+	counts map[string]int
+	timed  []string
+}
+
+func (f *fakeStatsD) Timing(name string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timed = append(f.timed, name)
+}
+
+func (f *fakeStatsD) Incr(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counts[name]++
+}
+
+func TestPipeline_UseFactory(t *testing.T) {
+	var infos []string
+	factory := func(info pipeline.StepInfo) pipeline.Middleware[int] {
+		infos = append(infos, fmt.Sprintf("%s@%d", info.Name, info.Index))
+		return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] { return next }
+	}
+	p := pipeline.New[int]().
+		ThenNamed("parse", pipeline.Identity[int]()).
+		UseFactoryNamed("labels", factory).
+		ThenNamed("save", pipeline.Identity[int]())
+	p.Compile()
+	sort.Strings(infos)
+	if fmt.Sprint(infos) != "[parse@0 save@1]" || fmt.Sprint(p.MiddlewareNames()) != "[labels]" {
+		t.Errorf("Expected one middleware per step, got %v named %v", infos, p.MiddlewareNames())
+	}
+}
+
+func TestPipeline_StatsDMiddleware(t *testing.T) {
+	client := &fakeStatsD{counts: make(map[string]int)}
+	p := pipeline.New[int]().
+		UseFactory(pipeline.StatsDMiddleware[int](client, "orders")).
+		ThenNamed("parse", pipeline.Identity[int]()).
+		ThenNamed("save", func(x int) (int, error) {
+			if x < 0 {
+				return x, errors.New("invalid")
+			}
+			return x, nil
+		})
+
+	p.Execute(1)
+	p.Execute(-1)
+	expected := map[string]int{"orders.parse.calls": 2, "orders.save.calls": 2, "orders.save.errors": 1}
+	if fmt.Sprint(client.counts) != fmt.Sprint(expected) {
+		t.Errorf("Expected counts %v, got %v", expected, client.counts)
+	}
+	if len(client.timed) != 4 || client.timed[0] != "orders.parse.duration" {
+		t.Errorf("Expected a timing per call, got %v", client.timed)
+	}
+}
@@ -0,0 +1,70 @@
+// =====================
+// finalize_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Finalizers(t *testing.T) {
+	var log []string
+	finalizer := func(name string) pipeline.Option[int] {
+		return pipeline.WithFinalizer(func(result int, err error) {
+			log = append(log, fmt.Sprintf("%s(%d, %v)", name, result, err))
+		})
+	}
+	errFail := errors.New("failure")
+	p := pipeline.New[int](finalizer("close-tx"), finalizer("remove-tmp")).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		Then(func(x int) (int, error) {
+			if x > 5 {
+				return x, errFail
+			}
+			return x, nil
+		})
+
+	p.Execute(1)
+	p.Execute(10)
+	expected := "[remove-tmp(2, <nil>) close-tx(2, <nil>) remove-tmp(11, failure) close-tx(11, failure)]"
+	if got := fmt.Sprint(log); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestPipeline_FinalizersOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		ran      []int
+		final    int
+		finalErr error
+	)
+	step := func(n int) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			ran = append(ran, n)
+			if n == 2 {
+				cancel()
+			}
+			return x + 1, nil
+		}
+	}
+	p := pipeline.New[int](pipeline.WithFinalizer(func(result int, err error) {
+		final, finalErr = result, err
+	})).Then(step(1)).Then(step(2)).Then(step(3)).Then(step(4))
+
+	out, err := p.ExecuteContext(ctx, 0)
+	if !errors.Is(err, context.Canceled) || out != 2 {
+		t.Fatalf("Expected (2, context.Canceled), got (%d, %v)", out, err)
+	}
+	if fmt.Sprint(ran) != "[1 2]" {
+		t.Errorf("Expected only steps 1 and 2 to run, got %v", ran)
+	}
+	if final != 2 || !errors.Is(finalErr, context.Canceled) {
+		t.Errorf("Expected finalizer to see (2, context.Canceled), got (%d, %v)", final, finalErr)
+	}
+}
@@ -0,0 +1,61 @@
+// =====================
+// group_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Group(t *testing.T) {
+	started := make(chan struct{})
+	group := pipeline.GroupContext(
+		func(ctx context.Context, x int) (int, error) { return x + 1, nil },
+		func(ctx context.Context, x int) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	)
+	p := pipeline.New[int]().
+		ThenContext(group.Run).
+		Then(pipeline.Wrap(func(x int) int { return x * 10 }))
+
+	go func() {
+		<-started
+		group.Cancel()
+	}()
+	out, err := p.Execute(1)
+	if err != nil || out != 20 {
+		t.Errorf("Expected the outer pipeline to continue with 2, got %d (%v)", out, err)
+	}
+	if group.Context().Err() == nil {
+		t.Errorf("Expected the group's context to be done")
+	}
+
+	// A cancelled group is skipped entirely.
+	if out, err := p.Execute(5); err != nil || out != 50 {
+		t.Errorf("Expected 50, got %d (%v)", out, err)
+	}
+}
+
+func TestPipeline_GroupErrors(t *testing.T) {
+	errFail := errors.New("failure")
+	group := pipeline.Group(
+		pipeline.Wrap(func(x int) int { return x + 1 }),
+		func(x int) (int, error) { return x, errFail },
+	)
+	if _, err := group.Run(context.Background(), 1); err != errFail {
+		t.Errorf("Expected %v, got %v", errFail, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := group.Run(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the caller's cancellation to be reported, got %v", err)
+	}
+}
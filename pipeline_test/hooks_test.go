@@ -0,0 +1,82 @@
+// =====================
+// hooks_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_PreAndPostSteps(t *testing.T) {
+	pre, post := 0, 0
+	p := pipeline.New[string](
+		pipeline.WithPreStep(func(s string) string { pre++; return strings.TrimSpace(s) }),
+		pipeline.WithPostStep(func(s string) string { post++; return s + "!" }),
+	).
+		Then(pipeline.Wrap(strings.ToUpper)).
+		Then(func(s string) (string, error) {
+			if s == "" {
+				return s, errors.New("empty")
+			}
+			return s + s, nil
+		})
+
+	out, err := p.Execute("  hi ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != "HIHI!" || pre != 1 || post != 1 {
+		t.Errorf("Expected HIHI! with one pre and post call, got %q (%d, %d)", out, pre, post)
+	}
+	if _, err := p.Execute("   "); err == nil || post != 1 {
+		t.Errorf("Expected failure to skip the post-step, got %v with %d post calls", err, post)
+	}
+}
+
+func TestPipeline_WithDefaultInput(t *testing.T) {
+	p := pipeline.New[string](
+		pipeline.WithPreStep(strings.ToUpper),
+		pipeline.WithDefaultInput("guest", func(s string) bool { return s == "" }),
+	).Then(pipeline.Wrap(func(s string) string { return "hello " + s }))
+
+	if out, _ := p.Execute(""); out != "hello GUEST" {
+		t.Errorf("Expected the default to be substituted before pre-steps, got %q", out)
+	}
+	if out, _ := p.Execute("ann"); out != "hello ANN" {
+		t.Errorf("Expected a set input to be kept, got %q", out)
+	}
+}
+
+func TestPipeline_InputAndOutputSchema(t *testing.T) {
+	var calls int
+	nonEmpty := func(s string) error {
+		if s == "" {
+			return errors.New("empty")
+		}
+		return nil
+	}
+	p := pipeline.New[string](
+		pipeline.WithPreStep(strings.TrimSpace),
+		pipeline.WithInputSchema(nonEmpty),
+		pipeline.WithOutputSchema(func(s string) error {
+			if len(s) > 4 {
+				return errors.New("too long")
+			}
+			return nil
+		}),
+	).Then(func(s string) (string, error) { calls++; return s + s, nil })
+
+	if out, err := p.Execute(" ab "); err != nil || out != "abab" {
+		t.Errorf("Expected abab, got %q (%v)", out, err)
+	}
+	if _, err := p.Execute("   "); !errors.Is(err, pipeline.ErrInvalidInput) || err.Error() != "pipeline: invalid input: empty" || calls != 1 {
+		t.Errorf("Expected ErrInvalidInput before any step ran, got %v after %d calls", err, calls)
+	}
+	if out, err := p.Execute("abc"); !errors.Is(err, pipeline.ErrInvalidOutput) || out != "abcabc" {
+		t.Errorf("Expected ErrInvalidOutput with the output, got %q (%v)", out, err)
+	}
+}
@@ -0,0 +1,120 @@
+// =====================
+// introspect_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_MiddlewareCount(t *testing.T) {
+	p := pipeline.New[int]()
+	if n := p.MiddlewareCount(); n != 0 {
+		t.Fatalf("Expected 0 middlewares, got %d", n)
+	}
+	p.Use(pipeline.Retry[int](2, nil)).UseContext(pipeline.RetryContext[int](2, nil))
+	if n := p.MiddlewareCount(); n != 2 {
+		t.Errorf("Expected 2 middlewares, got %d", n)
+	}
+}
+
+func TestPipeline_MiddlewareNames(t *testing.T) {
+	p := pipeline.New[int]().
+		UseNamed("retry", pipeline.Retry[int](2, nil)).
+		Use(pipeline.Retry[int](2, nil)).
+		UseContextNamed("retry-ctx", pipeline.RetryContext[int](2, nil))
+	names := p.MiddlewareNames()
+	expected := []string{"retry", "mw-1", "retry-ctx"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	}
+}
+
+func TestPipeline_String(t *testing.T) {
+	p := pipeline.New[int]()
+	if got := p.String(); got != "Pipeline[0 steps]" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+	p.UseNamed("retry", pipeline.Retry[int](2, nil)).ThenNamed("parse", pipeline.Identity[int]())
+	if got := p.String(); got != "Pipeline[1 step: parse; middleware: retry]" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+	p.Then(pipeline.Identity[int]())
+	expected := "Pipeline[2 steps: parse, step-1; middleware: retry]"
+	if got := fmt.Sprintf("%v", p); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestPipeline_Equal(t *testing.T) {
+	build := func(mw string, step string) *pipeline.Pipeline[int] {
+		return pipeline.New[int]().
+			UseNamed(mw, pipeline.Retry[int](2, nil)).
+			ThenNamed("parse", pipeline.Identity[int]()).
+			ThenNamed(step, pipeline.Wrap(func(x int) int { return x + 1 }))
+	}
+	expected := build("retry", "save")
+	if !build("retry", "save").Equal(expected) {
+		t.Errorf("Expected pipelines with the same structure to be equal")
+	}
+	if build("retry", "store").Equal(expected) || build("log", "save").Equal(expected) {
+		t.Errorf("Expected differing step or middleware names to make pipelines unequal")
+	}
+	if expected.Equal(nil) || !expected.Equal(expected) {
+		t.Errorf("Unexpected result comparing with nil or itself")
+	}
+}
+
+func TestPipeline_StepsByTag(t *testing.T) {
+	id := pipeline.Identity[int]()
+	tags := map[string]string{"team": "billing", "pii": "true"}
+	p := pipeline.New[int]().
+		ThenTagged(tags, id).
+		Then(id).
+		ThenTagged(map[string]string{"team": "search"}, id).
+		ThenTagged(map[string]string{"team": "billing"}, id)
+	tags["team"] = "changed"
+
+	if got := fmt.Sprint(p.StepsByTag("team", "billing")); got != "[0 3]" {
+		t.Errorf("Expected [0 3], got %s", got)
+	}
+	if got := fmt.Sprint(p.StepsByTag("pii", "true")); got != "[0]" {
+		t.Errorf("Expected [0], got %s", got)
+	}
+	if got := p.StepsByTag("team", "missing"); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", got)
+	}
+}
+
+func TestPipeline_UseForTag(t *testing.T) {
+	var audited []int
+	audit := func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			audited = append(audited, x)
+			return next(x)
+		}
+	}
+	inc := pipeline.Wrap(func(x int) int { return x + 1 })
+	p := pipeline.New[int]().
+		ThenTagged(map[string]string{"pii": "true"}, inc).
+		UseForTag("pii", "true", audit).
+		Then(inc).
+		ThenTagged(map[string]string{"pii": "false"}, inc).
+		ThenTagged(map[string]string{"pii": "true"}, inc)
+
+	out, err := p.Execute(0)
+	if err != nil || out != 4 {
+		t.Fatalf("Expected 4, got %d (%v)", out, err)
+	}
+	if fmt.Sprint(audited) != "[0 3]" {
+		t.Errorf("Expected only the pii steps to be audited, got %v", audited)
+	}
+}
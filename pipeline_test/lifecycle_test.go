@@ -0,0 +1,67 @@
+// =====================
+// lifecycle_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+type recordingService struct {
+	name     string
+	log      *[]string
+	startErr error
+}
+
+func (s *recordingService) Start() error {
+	*s.log = append(*s.log, "start "+s.name)
+	return s.startErr
+}
+
+func (s *recordingService) Stop(ctx context.Context) error {
+	*s.log = append(*s.log, "stop "+s.name)
+	return nil
+}
+
+func TestPipeline_StartStop(t *testing.T) {
+	var log []string
+	p := pipeline.New[int](
+		pipeline.WithService[int](&recordingService{name: "a", log: &log}),
+		pipeline.WithService[int](&recordingService{name: "b", log: &log}),
+	)
+	if err := p.Start(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected second Stop to be a no-op, got %v", err)
+	}
+	if got := fmt.Sprint(log); got != "[start a start b stop b stop a]" {
+		t.Errorf("Unexpected lifecycle order: %s", got)
+	}
+	if err := p.Start(); !errors.Is(err, pipeline.ErrPipelineStopped) {
+		t.Errorf("Expected ErrPipelineStopped, got %v", err)
+	}
+}
+
+func TestPipeline_StartFailureStopsStarted(t *testing.T) {
+	var log []string
+	errBoom := errors.New("boom")
+	p := pipeline.New[int](
+		pipeline.WithService[int](&recordingService{name: "a", log: &log}),
+		pipeline.WithService[int](&recordingService{name: "b", log: &log, startErr: errBoom}),
+	)
+	if err := p.Start(); err != errBoom {
+		t.Fatalf("Expected %v, got %v", errBoom, err)
+	}
+	if got := fmt.Sprint(log); got != "[start a start b stop a]" {
+		t.Errorf("Unexpected lifecycle order: %s", got)
+	}
+}
@@ -0,0 +1,64 @@
+// =====================
+// logging_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestPipeline_LogSampled(t *testing.T) {
+	logger := &recordingLogger{}
+	p := pipeline.New[int]().
+		Use(pipeline.LogSampled[int](logger, 10)).
+		Then(pipeline.Identity[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Execute(i)
+		}()
+	}
+	wg.Wait()
+	if len(logger.lines) != 3 {
+		t.Errorf("Expected calls 1, 11 and 21 to be logged, got %q", logger.lines)
+	}
+}
+
+func TestPipeline_LogSampledErrors(t *testing.T) {
+	logger := &recordingLogger{}
+	p := pipeline.New[int]().
+		Use(pipeline.LogSampledErrors[int](logger, 3)).
+		Then(func(x int) (int, error) {
+			if x%2 == 0 {
+				return x, errors.New("even")
+			}
+			return x, nil
+		})
+
+	for i := 0; i < 10; i++ {
+		p.Execute(i)
+	}
+	want := []string{"pipeline: error 1: input 0: even", "pipeline: error 4: input 6: even"}
+	if fmt.Sprint(logger.lines) != fmt.Sprint(want) {
+		t.Errorf("Expected %q, got %q", want, logger.lines)
+	}
+}
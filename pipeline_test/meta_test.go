@@ -0,0 +1,37 @@
+// =====================
+// meta_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ExecuteWithMeta(t *testing.T) {
+	p := pipeline.New[int]().
+		ThenMeta(func(x int) (int, pipeline.Meta, error) {
+			if x == 0 {
+				return 1, pipeline.Meta{"warning": "input defaulted"}, nil
+			}
+			return x, nil, nil
+		}).
+		Then(pipeline.Wrap(func(x int) int { return x * 2 })).
+		ThenMeta(func(x int) (int, pipeline.Meta, error) {
+			return x, pipeline.Meta{"final": x}, nil
+		})
+
+	out, meta, err := p.ExecuteWithMeta(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 2 || meta["warning"] != "input defaulted" || meta["final"] != 2 {
+		t.Errorf("Unexpected result %d with meta %v", out, meta)
+	}
+
+	plain, err := p.Execute(0)
+	if err != nil || plain != 2 {
+		t.Errorf("Expected Execute to ignore meta, got %d (%v)", plain, err)
+	}
+}
@@ -0,0 +1,274 @@
+// =====================
+// middleware_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Contract(t *testing.T) {
+	errNegativeIn := errors.New("negative input")
+	errNegativeOut := errors.New("negative output")
+	nonNegative := func(err error) func(int) error {
+		return func(x int) error {
+			if x < 0 {
+				return err
+			}
+			return nil
+		}
+	}
+	p := pipeline.New[int]().
+		Use(pipeline.Contract(nonNegative(errNegativeIn), nonNegative(errNegativeOut))).
+		Then(pipeline.Wrap(func(x int) int { return x - 10 }))
+
+	if out, err := p.Execute(15); err != nil || out != 5 {
+		t.Errorf("Expected 5, got %d (%v)", out, err)
+	}
+	if _, err := p.Execute(-1); err != errNegativeIn {
+		t.Errorf("Expected %v, got %v", errNegativeIn, err)
+	}
+	if _, err := p.Execute(3); err != errNegativeOut {
+		t.Errorf("Expected %v, got %v", errNegativeOut, err)
+	}
+
+	skip := pipeline.New[int]().
+		Use(pipeline.Contract[int](nil, nil)).
+		Then(pipeline.Wrap(func(x int) int { return x - 10 }))
+	if out, err := skip.Execute(3); err != nil || out != -7 {
+		t.Errorf("Expected nil checks to be skipped, got %d (%v)", out, err)
+	}
+}
+
+func TestPipeline_MaxSize(t *testing.T) {
+	p := pipeline.New[[]int]().
+		Use(pipeline.MaxSize(func(v []int) int { return len(v) }, 3)).
+		Then(pipeline.Wrap(func(v []int) []int { return append(v, 0) }))
+
+	if out, err := p.Execute([]int{1, 2}); err != nil || len(out) != 3 {
+		t.Errorf("Expected 3 elements, got %v (%v)", out, err)
+	}
+	if _, err := p.Execute([]int{1, 2, 3, 4}); !errors.Is(err, pipeline.ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for oversized input, got %v", err)
+	}
+	if _, err := p.Execute([]int{1, 2, 3}); !errors.Is(err, pipeline.ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for oversized output, got %v", err)
+	}
+}
+
+func TestPipeline_ThenSkipMiddleware(t *testing.T) {
+	var log []string
+	tag := func(name string) pipeline.Middleware[int] {
+		return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+			return func(x int) (int, error) {
+				log = append(log, name)
+				return next(x)
+			}
+		}
+	}
+	inc := pipeline.Wrap(func(x int) int { return x + 1 })
+	p := pipeline.New[int]().Use(tag("retry")).Use(tag("log")).
+		Then(inc).
+		ThenSkipMiddleware(inc).
+		ThenSkipMiddleware(inc, 0)
+	out, err := p.Execute(0)
+	if err != nil || out != 3 {
+		t.Fatalf("Expected 3, got %d (%v)", out, err)
+	}
+	if got := fmt.Sprint(log); got != "[retry log log]" {
+		t.Errorf("Expected retry and log, nothing, then only log; got %s", got)
+	}
+}
+
+func TestPipeline_RemoveMiddleware(t *testing.T) {
+	var log []string
+	tracer := func(name string) pipeline.Middleware[int] {
+		return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+			return func(x int) (int, error) {
+				log = append(log, name)
+				return next(x)
+			}
+		}
+	}
+	p := pipeline.New[int]().
+		UseNamed("outer", tracer("outer")).
+		UseNamed("inner", tracer("inner")).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenSkipMiddleware(pipeline.Wrap(func(x int) int { return x * 2 }), 0)
+
+	p.Execute(1)
+	if fmt.Sprint(log) != "[outer inner inner]" {
+		t.Fatalf("Unexpected middleware calls %v", log)
+	}
+
+	if !p.RemoveMiddleware("inner") {
+		t.Fatalf("Expected inner to be removed")
+	}
+	if p.RemoveMiddleware("inner") {
+		t.Errorf("Expected a second removal to report false")
+	}
+	log = nil
+	if out, _ := p.Execute(1); out != 4 {
+		t.Errorf("Expected steps to keep working, got %d", out)
+	}
+	if fmt.Sprint(log) != "[outer]" || fmt.Sprint(p.MiddlewareNames()) != "[outer]" {
+		t.Errorf("Expected only outer to remain, got calls %v and names %v", log, p.MiddlewareNames())
+	}
+
+	// New middleware keeps counting registration indices past removed ones.
+	p.Use(tracer("new"))
+	if names := p.MiddlewareNames(); fmt.Sprint(names) != "[outer mw-2]" {
+		t.Errorf("Expected the new middleware to be named mw-2, got %v", names)
+	}
+}
+
+func TestPipeline_Stack(t *testing.T) {
+	var log []string
+	tracer := func(name string) pipeline.Middleware[int] {
+		return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+			return func(x int) (int, error) {
+				log = append(log, name+">")
+				out, err := next(x)
+				log = append(log, "<"+name)
+				return out, err
+			}
+		}
+	}
+	step := pipeline.Wrap(func(x int) int { return x + 1 })
+
+	stacked := pipeline.New[int]().Use(pipeline.Stack(tracer("a"), tracer("b"))).Then(step)
+	stacked.Execute(1)
+	got := fmt.Sprint(log)
+
+	log = nil
+	pipeline.New[int]().Use(tracer("a")).Use(tracer("b")).Then(step).Execute(1)
+	if got != "[a> b> <b <a]" || got != fmt.Sprint(log) {
+		t.Errorf("Expected Stack to nest like separate Use calls, got %s and %v", got, log)
+	}
+}
+
+func TestPipeline_LimitConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	step := func(x int) (int, error) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		return x, nil
+	}
+	p := pipeline.New[int]().Use(pipeline.LimitConcurrency[int](2)).Then(step)
+	p.ExecuteBatchParallel(context.Background(), make([]int, 10), 10)
+	if got := peak.Load(); got != 2 {
+		t.Errorf("Expected at most 2 concurrent calls across executions, got %d", got)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking := pipeline.LimitConcurrencyFailFast[int](1)(func(x int) (int, error) {
+		close(started)
+		<-release
+		return x, nil
+	})
+	go blocking(1)
+	<-started
+	if out, err := blocking(2); !errors.Is(err, pipeline.ErrRateLimited) || out != 2 {
+		t.Errorf("Expected ErrRateLimited with the input, got %d (%v)", out, err)
+	}
+	close(release)
+
+	if out, err := pipeline.LimitConcurrencyFailFast[int](0)(pipeline.Identity[int]())(3); err != nil || out != 3 {
+		t.Errorf("Expected a limit of 0 to be treated as 1, got %d (%v)", out, err)
+	}
+}
+
+var allocSink []byte
+
+func TestPipeline_AllocGuardWhen(t *testing.T) {
+	p := pipeline.New[int]().
+		Use(pipeline.When(func(n int) bool { return n > 1000 }, pipeline.AllocGuard[int](64<<10))).
+		Then(func(n int) (int, error) {
+			allocSink = make([]byte, n)
+			return len(allocSink), nil
+		})
+
+	if out, err := p.Execute(1 << 20); !errors.Is(err, pipeline.ErrTooLarge) || out != 1<<20 {
+		t.Errorf("Expected ErrTooLarge with the input, got %d (%v)", out, err)
+	}
+	if out, err := p.Execute(1 << 10); err != nil || out != 1<<10 {
+		t.Errorf("Expected a small allocation to pass, got %d (%v)", out, err)
+	}
+	unguarded := pipeline.New[int]().
+		Use(pipeline.When(func(n int) bool { return false }, pipeline.AllocGuard[int](64<<10))).
+		Then(func(n int) (int, error) {
+			allocSink = make([]byte, n)
+			return len(allocSink), nil
+		})
+	if _, err := unguarded.Execute(1 << 20); err != nil {
+		t.Errorf("Expected When to skip the guard, got %v", err)
+	}
+}
+
+func TestPipeline_Recover(t *testing.T) {
+	boom := errors.New("boom")
+	p := pipeline.New[int]().
+		UseFactory(pipeline.Recover[int]()).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenNamed("explode", func(x int) (int, error) {
+			if x > 5 {
+				panic(boom)
+			}
+			panic("bad input")
+		})
+
+	out, err := p.Execute(1)
+	var panicErr pipeline.PanicError
+	if !errors.As(err, &panicErr) || out != 2 {
+		t.Fatalf("Expected a PanicError with the step input, got %d (%v)", out, err)
+	}
+	if panicErr.Value != "bad input" || panicErr.Step != "explode" || panicErr.Index != 1 || len(panicErr.Stack) == 0 {
+		t.Errorf("Unexpected panic details %+v", panicErr)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Errorf("Expected no underlying error for a string panic, got %v", errors.Unwrap(err))
+	}
+	if _, err := p.Execute(10); !errors.Is(err, boom) {
+		t.Errorf("Expected errors.Is to match the panicked error, got %v", err)
+	}
+}
+
+type debugKey struct{}
+
+func TestPipeline_WhenContext(t *testing.T) {
+	var traced []int
+	trace := func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			traced = append(traced, x)
+			return next(x)
+		}
+	}
+	debug := func(ctx context.Context) bool { return ctx.Value(debugKey{}) == true }
+	p := pipeline.New[int]().
+		UseContext(pipeline.WhenContext(debug, trace)).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		Then(pipeline.Wrap(func(x int) int { return x * 2 }))
+
+	if out, _ := p.Execute(1); out != 4 || len(traced) != 0 {
+		t.Errorf("Expected no tracing without the flag, got %d with %v", out, traced)
+	}
+	ctx := context.WithValue(context.Background(), debugKey{}, true)
+	if out, _ := p.ExecuteContext(ctx, 1); out != 4 || fmt.Sprint(traced) != "[1 2]" {
+		t.Errorf("Expected both steps traced with the flag, got %d with %v", out, traced)
+	}
+}
@@ -0,0 +1,152 @@
+// =====================
+// once_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ExecuteOnce(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p := pipeline.New[int]().Then(func(x int) (int, error) {
+		if runs.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return x * 10, nil
+	})
+
+	results := make([]int, 3)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = p.ExecuteOnce("order-1", 1)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1], _ = p.ExecuteOnce("order-1", 2)
+	}()
+	// A different key is not blocked by order-1.
+	if out, _ := p.ExecuteOnce("order-2", 3); out != 30 {
+		t.Errorf("Expected 30 for another key, got %d", out)
+	}
+	time.Sleep(20 * time.Millisecond) // let the second order-1 call block
+	close(release)
+	wg.Wait()
+
+	if results[0] != 10 || results[1] != 10 || runs.Load() != 2 {
+		t.Errorf("Expected the second order-1 call to share the first result, got %v after %d runs", results, runs.Load())
+	}
+	if out, _ := p.ExecuteOnce("order-1", 4); out != 40 {
+		t.Errorf("Expected a fresh execution once the first finished, got %d", out)
+	}
+}
+
+func TestPipeline_ExactlyOnce(t *testing.T) {
+	var charges atomic.Int32
+	charge := func(order string) (string, error) {
+		charges.Add(1)
+		return order + ":charged", nil
+	}
+	store := pipeline.NewMemoryIdempotencyStore[string]()
+	key := func(order string) string { return order }
+	p := pipeline.New[string]().Then(pipeline.ExactlyOnce(key, store, charge))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if out, err := p.Execute("o1"); err != nil || out != "o1:charged" {
+				t.Errorf("Expected o1:charged, got %q (%v)", out, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := charges.Load(); n != 1 {
+		t.Errorf("Expected one charge, got %d", n)
+	}
+
+	restarted := pipeline.New[string]().Then(pipeline.ExactlyOnce(key, store, charge))
+	restarted.Execute("o1")
+	restarted.Execute("o2")
+	if n := charges.Load(); n != 2 {
+		t.Errorf("Expected the stored result to be replayed, got %d charges", n)
+	}
+}
+
+func TestPipeline_ExecuteOncePanic(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p := pipeline.New[int]().Then(func(x int) (int, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+
+	repanicked := make(chan any, 1)
+	go func() {
+		defer func() { repanicked <- recover() }()
+		p.ExecuteOnce("order-1", 1)
+	}()
+	<-started
+	waited := make(chan error, 1)
+	go func() {
+		_, err := p.ExecuteOnce("order-1", 2)
+		waited <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // let the second call block
+	close(release)
+
+	var panicErr pipeline.PanicError
+	if err := <-waited; !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Errorf("Expected the waiting call to get a PanicError, got %v", err)
+	}
+	if v := <-repanicked; v != "boom" {
+		t.Errorf("Expected the panic to continue in the first call, got %v", v)
+	}
+}
+
+func TestPipeline_ExactlyOncePanic(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	charge := func(order string) (string, error) {
+		close(started)
+		<-release
+		panic("card declined")
+	}
+	store := pipeline.NewMemoryIdempotencyStore[string]()
+	step := pipeline.ExactlyOnce(func(order string) string { return order }, store, charge)
+
+	go func() {
+		defer func() { recover() }()
+		step("o1")
+	}()
+	<-started
+	waited := make(chan error, 1)
+	go func() {
+		_, err := step("o1")
+		waited <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // let the duplicate block
+	close(release)
+
+	if err := <-waited; err == nil {
+		t.Error("Expected the duplicate of a panicking charge to fail")
+	}
+	if _, ok, _ := store.Load("o1"); ok {
+		t.Error("Expected nothing to be stored for a panicking charge")
+	}
+}
@@ -6,7 +6,11 @@ package pipeline_test_test
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/TheOrchestraX/pipeline"
 )
@@ -66,6 +70,80 @@ func TestPipeline_Middleware(t *testing.T) {
 	}
 }
 
+func TestPipeline_MiddlewareAppliesToEarlierSteps(t *testing.T) {
+	var logs []string
+	tracer := func(name string) pipeline.Middleware[int] {
+		return func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+			return func(x int) (int, error) {
+				logs = append(logs, name)
+				return next(x)
+			}
+		}
+	}
+	p := pipeline.New[int]().
+		Use(tracer("outer")).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+	p.Compile()
+	p.Use(tracer("inner"))
+
+	out, err := p.Execute(1)
+	if err != nil || out != 2 {
+		t.Fatalf("Expected 2, got %d (%v)", out, err)
+	}
+	if fmt.Sprint(logs) != "[outer inner]" {
+		t.Errorf("Expected both middlewares in registration order, got %v", logs)
+	}
+}
+
+func TestPipeline_Match(t *testing.T) {
+	label := func(s string) pipeline.StepFunc[string] {
+		return pipeline.Wrap(func(string) string { return s })
+	}
+	classify := pipeline.Match[string]().
+		Case(func(s string) bool { return strings.HasPrefix(s, "ERR") }, label("error")).
+		Case(func(s string) bool { return strings.HasPrefix(s, "E") }, label("e-word")).
+		Default(label("other")).
+		Build()
+
+	for input, expected := range map[string]string{"ERR42": "error", "EOF": "e-word", "ok": "other"} {
+		if out, _ := classify(input); out != expected {
+			t.Errorf("classify(%q): expected %s, got %s", input, expected, out)
+		}
+	}
+	passthrough := pipeline.Match[string]().Case(func(s string) bool { return s == "" }, label("empty")).Build()
+	if out, _ := passthrough("kept"); out != "kept" {
+		t.Errorf("Expected unmatched input to pass through without a default, got %q", out)
+	}
+}
+
+func TestPipeline_ExecuteReverse(t *testing.T) {
+	var log []string
+	step := func(name string) pipeline.StepFunc[string] {
+		return func(s string) (string, error) {
+			log = append(log, name)
+			return s + name, nil
+		}
+	}
+	mw := func(next pipeline.StepFunc[string]) pipeline.StepFunc[string] {
+		return func(s string) (string, error) {
+			log = append(log, "mw")
+			return next(s)
+		}
+	}
+	p := pipeline.New[string]().Use(mw).Then(step("a")).Then(step("b")).Then(step("c"))
+
+	out, err := p.ExecuteReverse("")
+	if err != nil || out != "cba" {
+		t.Fatalf("Expected cba, got %q (%v)", out, err)
+	}
+	if fmt.Sprint(log) != "[mw c mw b mw a]" {
+		t.Errorf("Expected each step wrapped as usual, in reverse order, got %v", log)
+	}
+	if out, _ := p.Execute(""); out != "abc" {
+		t.Errorf("Expected forward execution to be unaffected, got %q", out)
+	}
+}
+
 func TestPipeline_Conditional(t *testing.T) {
 	inc := pipeline.Wrap(func(x int) int { return x + 1 })
 	dec := pipeline.Wrap(func(x int) int { return x - 1 })
@@ -99,3 +177,463 @@ func TestPipeline_Parallel(t *testing.T) {
 		t.Errorf("Expected 10, got %d", out)
 	}
 }
+
+func TestPipeline_Isolated(t *testing.T) {
+	clone := func(m map[string]int) map[string]int {
+		out := make(map[string]int, len(m))
+		for k, v := range m {
+			out[k] = v
+		}
+		return out
+	}
+	mutate := func(m map[string]int) (map[string]int, error) {
+		m["count"]++
+		return m, nil
+	}
+	input := map[string]int{"count": 1}
+	out, err := pipeline.New[map[string]int]().
+		Then(pipeline.Isolated(clone, mutate)).
+		Execute(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if input["count"] != 1 {
+		t.Errorf("Expected input to be untouched, got %d", input["count"])
+	}
+	if out["count"] != 2 {
+		t.Errorf("Expected 2, got %d", out["count"])
+	}
+}
+
+func TestPipeline_SequentialParallel(t *testing.T) {
+	var order []int
+	step := func(n int) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			order = append(order, n)
+			return x * n, nil
+		}
+	}
+	combiner := func(results []int) (int, error) {
+		sum := 0
+		for _, v := range results {
+			sum += v
+		}
+		return sum, nil
+	}
+	steps := []pipeline.StepFunc[int]{step(1), step(2), step(3)}
+	seq, err := pipeline.New[int]().Then(pipeline.SequentialParallel(combiner, steps...)).Execute(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(order) != "[1 2 3]" {
+		t.Errorf("Expected steps to run in order, got %v", order)
+	}
+	if seq != 12 {
+		t.Errorf("Expected 12, got %d", seq)
+	}
+	f1 := pipeline.Wrap(func(x int) int { return x + 1 })
+	f2 := pipeline.Wrap(func(x int) int { return x * 2 })
+	par, _ := pipeline.New[int]().Then(pipeline.Parallel(combiner, f1, f2)).Execute(3)
+	seqPair, _ := pipeline.New[int]().Then(pipeline.SequentialParallel(combiner, f1, f2)).Execute(3)
+	if par != seqPair {
+		t.Errorf("Expected matching outputs, got %d and %d", par, seqPair)
+	}
+}
+
+func TestPipeline_ParallelNamed(t *testing.T) {
+	steps := map[string]pipeline.StepFunc[int]{
+		"base":    pipeline.Wrap(func(x int) int { return x }),
+		"pricing": pipeline.Wrap(func(x int) int { return x * 100 }),
+	}
+	combiner := func(results map[string]int) (int, error) {
+		return results["pricing"] - results["base"], nil
+	}
+	out, err := pipeline.New[int]().Then(pipeline.ParallelNamed(combiner, steps)).Execute(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 198 {
+		t.Errorf("Expected 198, got %d", out)
+	}
+
+	errDown := errors.New("down")
+	steps["tax"] = func(x int) (int, error) { return 0, errDown }
+	out, err = pipeline.New[int]().Then(pipeline.ParallelNamed(combiner, steps)).Execute(2)
+	if !errors.Is(err, errDown) {
+		t.Fatalf("Expected %v, got %v", errDown, err)
+	}
+	if err.Error() != "tax: down" || out != 2 {
+		t.Errorf("Expected named error and original input, got %q and %d", err, out)
+	}
+}
+
+func TestPipeline_ConditionalThen(t *testing.T) {
+	double := pipeline.Wrap(func(x int) int { return x * 2 })
+	p := pipeline.New[int]().Then(pipeline.ConditionalThen(func(x int) bool { return x > 0 }, double))
+
+	pos, _ := p.Execute(3)
+	neg, _ := p.Execute(-3)
+	if pos != 6 || neg != -3 {
+		t.Errorf("ConditionalThen failed, got %d and %d", pos, neg)
+	}
+}
+
+func TestPipeline_ParallelResults(t *testing.T) {
+	errFail := errors.New("failure")
+	run := pipeline.ParallelResults(
+		pipeline.Wrap(func(x int) int { return x + 1 }),
+		func(x int) (int, error) { return 0, errFail },
+		pipeline.Wrap(func(x int) int { return x * 3 }),
+	)
+	results, errs := run(5)
+	if results[0] != 6 || results[2] != 15 {
+		t.Errorf("Expected [6 _ 15], got %v", results)
+	}
+	if errs[0] != nil || errs[1] != errFail || errs[2] != nil {
+		t.Errorf("Expected only the second step to fail, got %v", errs)
+	}
+}
+
+func TestPipeline_ThenOrdered(t *testing.T) {
+	var trace []string
+	step := func(name string) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			trace = append(trace, name)
+			return x, nil
+		}
+	}
+	p := pipeline.New[int]().
+		ThenOrdered(10, step("late")).
+		Then(step("default-a")).
+		ThenOrdered(-5, step("early")).
+		Then(step("default-b")).
+		ThenOrdered(10, step("late-2"))
+	if _, err := p.Execute(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "[early default-a default-b late late-2]"
+	if got := fmt.Sprint(trace); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestPipeline_Swap(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	p := pipeline.New[int]().
+		Then(func(x int) (int, error) {
+			close(entered)
+			<-release
+			return x + 1, nil
+		}).
+		Then(pipeline.Wrap(func(x int) int { return x * 2 }))
+
+	done := make(chan int)
+	go func() {
+		out, _ := p.Execute(1)
+		done <- out
+	}()
+	<-entered
+	p.Swap([]pipeline.StepFunc[int]{pipeline.Wrap(func(x int) int { return x - 1 })})
+	close(release)
+	if inFlight := <-done; inFlight != 4 {
+		t.Errorf("Expected in-flight execution to finish with old steps (4), got %d", inFlight)
+	}
+	if out, _ := p.Execute(1); out != 0 {
+		t.Errorf("Expected new steps to apply (0), got %d", out)
+	}
+}
+
+func TestPipeline_SwapConcurrent(t *testing.T) {
+	inc := pipeline.Wrap(func(x int) int { return x + 1 })
+	p := pipeline.New[int]().Then(inc)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if out, _ := p.Execute(0); out < 1 || out > 2 {
+				t.Errorf("Unexpected output %d", out)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			p.Swap([]pipeline.StepFunc[int]{inc, inc})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPipeline_RequireSteps(t *testing.T) {
+	p := pipeline.New[int](pipeline.WithRequireSteps[int]())
+	if !p.IsEmpty() {
+		t.Fatalf("Expected new pipeline to be empty")
+	}
+	if _, err := p.Execute(1); !errors.Is(err, pipeline.ErrEmptyPipeline) {
+		t.Errorf("Expected ErrEmptyPipeline, got %v", err)
+	}
+	p.Then(pipeline.Identity[int]())
+	if p.IsEmpty() {
+		t.Errorf("Expected pipeline with a step not to be empty")
+	}
+	if out, err := p.Execute(1); err != nil || out != 1 {
+		t.Errorf("Expected 1, got %d (%v)", out, err)
+	}
+
+	if out, err := pipeline.New[int]().Execute(7); err != nil || out != 7 {
+		t.Errorf("Expected default pass-through, got %d (%v)", out, err)
+	}
+}
+
+func TestPipeline_Catch(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errDown := errors.New("down")
+	lookup := func(x int) (int, error) {
+		switch x {
+		case 1:
+			return 0, errNotFound
+		case 2:
+			return 0, errDown
+		}
+		return x * 10, nil
+	}
+	step := pipeline.Catch(lookup, func(input int, err error) (int, error) {
+		if errors.Is(err, errNotFound) {
+			return -input, nil
+		}
+		return input, fmt.Errorf("lookup %d: %w", input, err)
+	})
+	if out, err := step(3); err != nil || out != 30 {
+		t.Errorf("Expected 30, got %d (%v)", out, err)
+	}
+	if out, err := step(1); err != nil || out != -1 {
+		t.Errorf("Expected recovered -1, got %d (%v)", out, err)
+	}
+	if _, err := step(2); !errors.Is(err, errDown) || err.Error() != "lookup 2: down" {
+		t.Errorf("Expected translated error, got %v", err)
+	}
+}
+
+func TestPipeline_ParallelNilCombiner(t *testing.T) {
+	var sent atomic.Int32
+	notify := func(x int) (int, error) {
+		sent.Add(1)
+		return x * 100, nil
+	}
+	out, err := pipeline.New[int]().Then(pipeline.Parallel(nil, notify, notify, notify)).Execute(7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 7 || sent.Load() != 3 {
+		t.Errorf("Expected input 7 after 3 notifications, got %d after %d", out, sent.Load())
+	}
+
+	errFail := errors.New("failure")
+	failing := func(x int) (int, error) { return x, errFail }
+	if _, err := pipeline.Parallel(nil, notify, failing)(7); err != errFail {
+		t.Errorf("Expected %v, got %v", errFail, err)
+	}
+}
+
+func TestPipeline_Bracket(t *testing.T) {
+	var released []int
+	acquire := func(x int) (int, error) {
+		if x < 0 {
+			return x, errors.New("cannot open")
+		}
+		return x + 100, nil
+	}
+	errFail := errors.New("failure")
+	use := func(x int) (int, error) {
+		if x > 150 {
+			return x, errFail
+		}
+		return x * 2, nil
+	}
+	step := pipeline.Bracket(acquire, use, func(x int) { released = append(released, x) })
+
+	if out, err := step(1); err != nil || out != 202 {
+		t.Errorf("Expected 202, got %d (%v)", out, err)
+	}
+	if _, err := step(60); err != errFail {
+		t.Errorf("Expected %v, got %v", errFail, err)
+	}
+	if _, err := step(-1); err == nil {
+		t.Errorf("Expected the acquire error")
+	}
+	if fmt.Sprint(released) != "[101 160]" {
+		t.Errorf("Expected release after success and failure but not failed acquire, got %v", released)
+	}
+}
+
+func TestPipeline_ParallelOrderedCommit(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		commits []string
+	)
+	branch := func(name string, delay time.Duration, fail bool) pipeline.TwoPhase[int] {
+		return pipeline.TwoPhase[int]{
+			Compute: func(x int) (int, error) {
+				time.Sleep(delay)
+				if fail {
+					return x, errors.New(name + " failed")
+				}
+				return x + 1, nil
+			},
+			Commit: func(x int) (int, error) {
+				mu.Lock()
+				commits = append(commits, name)
+				mu.Unlock()
+				return x * 10, nil
+			},
+		}
+	}
+	step := pipeline.ParallelOrderedCommit(pipeline.SumCombiner[int],
+		branch("db", 20*time.Millisecond, false),
+		branch("cache", 0, false),
+		branch("index", 10*time.Millisecond, false))
+
+	out, err := step(1)
+	if err != nil || out != 60 {
+		t.Fatalf("Expected 60, got %d (%v)", out, err)
+	}
+	if fmt.Sprint(commits) != "[db cache index]" {
+		t.Errorf("Expected commits in registration order, got %v", commits)
+	}
+
+	commits = nil
+	failing := pipeline.ParallelOrderedCommit(nil, branch("db", 0, false), branch("cache", 0, true))
+	if _, err := failing(1); err == nil || len(commits) != 0 {
+		t.Errorf("Expected a compute failure to skip every commit, got %v after %v", err, commits)
+	}
+}
+
+func TestPipeline_Focus(t *testing.T) {
+	type user struct {
+		Name  string
+		Email string
+	}
+	normalize := func(s string) (string, error) {
+		if s == "" {
+			return s, errors.New("missing email")
+		}
+		return strings.ToLower(strings.TrimSpace(s)), nil
+	}
+	email := pipeline.Focus(
+		func(u user) string { return u.Email },
+		func(u user, e string) user { u.Email = e; return u },
+		normalize,
+	)
+	out, err := pipeline.New[user]().Then(email).Execute(user{Name: "Ada", Email: " Ada@Example.COM "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != (user{Name: "Ada", Email: "ada@example.com"}) {
+		t.Errorf("Unexpected user %+v", out)
+	}
+	if _, err := email(user{Name: "Bob"}); err == nil {
+		t.Errorf("Expected the field step's error to propagate")
+	}
+}
+
+func TestPipeline_FromSteps(t *testing.T) {
+	steps := []pipeline.StepFunc[int]{
+		pipeline.Wrap(func(x int) int { return x + 1 }),
+		pipeline.Wrap(func(x int) int { return x * 3 }),
+	}
+	var built, manual []string
+	p := pipeline.FromSteps(steps, tracing("a", &built), tracing("b", &built))
+	q := pipeline.New[int]().Use(tracing("a", &manual)).Use(tracing("b", &manual)).Then(steps[0]).Then(steps[1])
+
+	out, err := p.Execute(1)
+	want, _ := q.Execute(1)
+	if err != nil || out != want {
+		t.Errorf("Expected %d, got %d (%v)", want, out, err)
+	}
+	if fmt.Sprint(built) != fmt.Sprint(manual) || !p.Equal(q) {
+		t.Errorf("Expected the same nesting as building step by step, got %v and %v", built, manual)
+	}
+}
+
+func TestPipeline_MaybeParallel(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	step := func(n int) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			cur := inFlight.Add(1)
+			for old := peak.Load(); cur > old && !peak.CompareAndSwap(old, cur); old = peak.Load() {
+			}
+			time.Sleep(20 * time.Millisecond)
+			inFlight.Add(-1)
+			return x * n, nil
+		}
+	}
+	parallel := true
+	p := pipeline.New[int]().Then(pipeline.MaybeParallel(func() bool { return parallel }, sumCombiner, step(1), step(2), step(3)))
+
+	concurrent, err := p.Execute(2)
+	if err != nil || peak.Load() < 2 {
+		t.Errorf("Expected concurrent branches, got peak %d (%v)", peak.Load(), err)
+	}
+	parallel = false
+	peak.Store(0)
+	sequential, err := p.Execute(2)
+	if err != nil || peak.Load() != 1 {
+		t.Errorf("Expected one branch at a time, got peak %d (%v)", peak.Load(), err)
+	}
+	if concurrent != 12 || sequential != concurrent {
+		t.Errorf("Expected 12 in both modes, got %d and %d", concurrent, sequential)
+	}
+}
+
+func TestPipeline_Tee(t *testing.T) {
+	side := make(chan int, 1)
+	var dropped atomic.Uint64
+	p := pipeline.New[int]().
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		Then(pipeline.TeeNonBlocking(side, &dropped)).
+		Then(pipeline.Wrap(func(x int) int { return x * 10 }))
+
+	for i := 0; i < 3; i++ {
+		if out, err := p.Execute(i); err != nil || out != (i+1)*10 {
+			t.Errorf("Expected %d, got %d (%v)", (i+1)*10, out, err)
+		}
+	}
+	if v := <-side; v != 1 || dropped.Load() != 2 {
+		t.Errorf("Expected 1 on the side channel and two drops, got %d and %d", v, dropped.Load())
+	}
+
+	blocking := make(chan int)
+	go func() {
+		for v := range blocking {
+			side <- v
+		}
+	}()
+	if out, _ := pipeline.Tee(blocking)(7); out != 7 || <-side != 7 {
+		t.Error("Expected the blocking tee to deliver the value and pass it through")
+	}
+	close(blocking)
+}
+
+func TestPipeline_ParallelZeroSteps(t *testing.T) {
+	var got []int
+	called := false
+	combiner := func(results []int) (int, error) {
+		called, got = true, results
+		return 42, nil
+	}
+	if out, err := pipeline.Parallel(combiner)(7); err != nil || out != 42 || !called || len(got) != 0 {
+		t.Errorf("Expected the combiner to be called with no results, got %d (%v), called=%v with %v", out, err, called, got)
+	}
+	if out, err := pipeline.Parallel[int](nil)(7); err != nil || out != 7 {
+		t.Errorf("Expected a nil combiner to return the input, got %d (%v)", out, err)
+	}
+
+	called = false
+	if out, err := pipeline.ParallelRequireSteps(combiner)(7); !errors.Is(err, pipeline.ErrEmptyPipeline) || out != 7 || called {
+		t.Errorf("Expected ErrEmptyPipeline with the input, got %d (%v)", out, err)
+	}
+	step := pipeline.Wrap(func(x int) int { return x + 1 })
+	if out, err := pipeline.ParallelRequireSteps(sumCombiner, step, step)(1); err != nil || out != 4 {
+		t.Errorf("Expected 4 with steps, got %d (%v)", out, err)
+	}
+}
@@ -0,0 +1,62 @@
+// =====================
+// pipelinehttp_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+	"github.com/TheOrchestraX/pipeline/pipelinehttp"
+)
+
+func TestPipelinehttp_Handler(t *testing.T) {
+	p := pipeline.New[int]().Then(func(x int) (int, error) {
+		if x > 100 {
+			return x, fmt.Errorf("order too big: %w", pipeline.ErrTooLarge)
+		}
+		return x * 2, nil
+	})
+	decode := func(r *http.Request) (int, error) {
+		var x int
+		err := json.NewDecoder(r.Body).Decode(&x)
+		return x, err
+	}
+	encode := func(w http.ResponseWriter, x int) error {
+		return json.NewEncoder(w).Encode(x)
+	}
+	h := pipelinehttp.Handler(p, decode, encode)
+
+	cases := []struct {
+		body, wantBody string
+		wantCode       int
+	}{
+		{"21", "42\n", http.StatusOK},
+		{"not json", "", http.StatusBadRequest},
+		{"500", "", http.StatusRequestEntityTooLarge},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body)))
+		if rec.Code != c.wantCode {
+			t.Errorf("Body %q: expected status %d, got %d", c.body, c.wantCode, rec.Code)
+		}
+		if c.wantBody != "" && rec.Body.String() != c.wantBody {
+			t.Errorf("Body %q: expected response %q, got %q", c.body, c.wantBody, rec.Body.String())
+		}
+	}
+
+	teapot := pipelinehttp.Handler(p, decode, encode, pipelinehttp.WithErrorMapper(func(error) int {
+		return http.StatusTeapot
+	}))
+	rec := httptest.NewRecorder()
+	teapot.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("500")))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("Expected custom mapper status, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,46 @@
+// =====================
+// pipelineio_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+	"github.com/TheOrchestraX/pipeline/pipelineio"
+)
+
+func TestPipelineio_StreamLines(t *testing.T) {
+	p := pipeline.New[string]().Then(func(s string) (string, error) {
+		if s == "" {
+			return s, errors.New("blank line")
+		}
+		return strings.ToUpper(s), nil
+	})
+
+	got := drain(pipelineio.StreamLines(context.Background(), strings.NewReader("a\n\nb\r\nc"), p))
+	if len(got) != 4 {
+		t.Fatalf("Expected 4 results, got %v", got)
+	}
+	if got[0].Value != "A" || got[1].Err == nil || got[2].Value != "B" || got[3].Value != "C" {
+		t.Errorf("Unexpected results %v", got)
+	}
+}
+
+func TestPipelineio_StreamLinesMaxLineSize(t *testing.T) {
+	p := pipeline.New[string]().Then(pipeline.Identity[string]())
+	input := "short\n" + strings.Repeat("x", 100) + "\nnever\n"
+
+	got := drain(pipelineio.StreamLines(context.Background(), strings.NewReader(input), p, pipelineio.WithMaxLineSize(64)))
+	if len(got) != 2 || got[0].Value != "short" || !errors.Is(got[1].Err, bufio.ErrTooLong) {
+		t.Errorf("Expected the long line to stop the stream with bufio.ErrTooLong, got %v", got)
+	}
+	long := drain(pipelineio.StreamLines(context.Background(), strings.NewReader(input), p, pipelineio.WithMaxLineSize(1024)))
+	if len(long) != 3 || len(long[1].Value) != 100 {
+		t.Errorf("Expected a larger buffer to accept the line, got %v", long)
+	}
+}
@@ -0,0 +1,93 @@
+// =====================
+// pipelinetest_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+	"github.com/TheOrchestraX/pipeline/pipelinetest"
+)
+
+// recordingTB captures failures reported by the pipelinetest helpers.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestPipelinetest_Assertions(t *testing.T) {
+	abs := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}))
+	pipelinetest.AssertPure(t, abs, -4)
+	pipelinetest.AssertIdempotent(t, abs, -4)
+
+	calls := 0
+	counter := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int {
+		calls++
+		return x + calls
+	}))
+	rec := &recordingTB{TB: t}
+	pipelinetest.AssertPure(rec, counter, 1)
+	pipelinetest.AssertIdempotent(rec, counter, 1)
+	if len(rec.failures) != 2 {
+		t.Errorf("Expected 2 failures, got %v", rec.failures)
+	}
+}
+
+func TestPipelinetest_Check(t *testing.T) {
+	abs := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}))
+	nonNegative := func(in, out int, err error) error {
+		// math.MinInt has no positive counterpart, so it stays negative.
+		if out < 0 && in != -in {
+			return errors.New("negative output")
+		}
+		return err
+	}
+	pipelinetest.Check(t, abs, nonNegative, nil)
+	pipelinetest.AssertInvariant(t, abs, -7, nonNegative)
+}
+
+func TestPipelinetest_Spy(t *testing.T) {
+	p := pipeline.New[string]().
+		Then(pipeline.Wrap(strings.TrimSpace)).
+		Then(pipeline.Wrap(strings.ToUpper)).
+		Then(func(s string) (string, error) {
+			if s == "" {
+				return s, errors.New("empty")
+			}
+			return s + "!", nil
+		})
+	spy := pipelinetest.NewSpy(p)
+
+	p.Execute("  hi ")
+	p.Execute("   ")
+	if got := spy.Inputs(1); fmt.Sprint(got) != "[hi ]" {
+		t.Errorf("Expected step 1 to receive the trimmed values, got %q", got)
+	}
+	if got := spy.Outputs(2); fmt.Sprint(got) != "[HI!]" {
+		t.Errorf("Expected only the successful output of step 2, got %q", got)
+	}
+	spy.Reset()
+	if len(spy.Inputs(0)) != 0 {
+		t.Error("Expected Reset to clear the recordings")
+	}
+}
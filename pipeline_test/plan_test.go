@@ -0,0 +1,86 @@
+// =====================
+// plan_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Plan(t *testing.T) {
+	var ran []string
+	step := func(name string, f func(int) int) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			ran = append(ran, name)
+			return f(x), nil
+		}
+	}
+	inc := step("inc", func(x int) int { return x + 1 })
+	dec := step("dec", func(x int) int { return x - 1 })
+	double := step("double", func(x int) int { return x * 2 })
+
+	p := pipeline.New[int]().
+		ThenNamed("validate", step("validate", func(x int) int { return x })).
+		ThenBranch(pipeline.NamedConditional(func(x int) bool { return x > 0 }, "inc", inc, "dec", dec)).
+		ThenBranch(pipeline.NamedConditional(func(x int) bool { return x > 10 }, "double", double, "", nil))
+
+	for _, tc := range []struct {
+		input    int
+		expected string
+	}{
+		{5, "[validate step-1/inc]"},
+		{-5, "[validate step-1/dec]"},
+		{20, "[validate step-1/inc step-2/double]"},
+	} {
+		names, err := p.Plan(tc.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(names); got != tc.expected {
+			t.Errorf("Plan(%d): expected %s, got %s", tc.input, tc.expected, got)
+		}
+	}
+	if len(ran) != 0 {
+		t.Errorf("Expected Plan not to run any step, ran %v", ran)
+	}
+
+	if out, _ := p.Execute(20); out != 42 || fmt.Sprint(ran) != "[validate inc double]" {
+		t.Errorf("Expected branches to execute normally, got %d after %v", out, ran)
+	}
+	if out, _ := p.Execute(5); out != 6 {
+		t.Errorf("Expected a nil else branch to pass through, got %d", out)
+	}
+
+	empty := pipeline.New[int](pipeline.WithRequireSteps[int]())
+	if _, err := empty.Plan(1); !errors.Is(err, pipeline.ErrEmptyPipeline) {
+		t.Errorf("Expected ErrEmptyPipeline, got %v", err)
+	}
+}
+
+func TestPipeline_PlanPreSteps(t *testing.T) {
+	pos := func(x int) (int, error) { return x, nil }
+	neg := func(x int) (int, error) { return -x, nil }
+	p := pipeline.New[int](
+		pipeline.WithDefaultInput(7, func(x int) bool { return x == 0 }),
+		pipeline.WithInputSchema(func(x int) error {
+			if x > 100 {
+				return errors.New("too big")
+			}
+			return nil
+		}),
+	).ThenBranch(pipeline.NamedConditional(func(x int) bool { return x > 0 }, "pos", pos, "neg", neg))
+
+	if names, err := p.Plan(0); err != nil || fmt.Sprint(names) != "[step-0/pos]" {
+		t.Errorf("Expected Plan to route the default input, got %v (%v)", names, err)
+	}
+	if out, _ := p.Execute(0); out != 7 {
+		t.Errorf("Expected Execute to take the planned branch, got %d", out)
+	}
+	if _, err := p.Plan(200); !errors.Is(err, pipeline.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// =====================
+// queue_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Queue(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		sum int
+	)
+	sink := func(r pipeline.Result[int]) {
+		mu.Lock()
+		sum += r.Value
+		mu.Unlock()
+	}
+	p := pipeline.New[int](pipeline.WithQueue(2, false, sink)).
+		Then(pipeline.Wrap(func(x int) int { return x * 10 }))
+
+	// Before consumers start, the queue fills up.
+	if err := p.Enqueue(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.Enqueue(2)
+	if err := p.Enqueue(3); !errors.Is(err, pipeline.ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	if err := p.StartConsumers(2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if sum != 30 {
+		t.Errorf("Expected Stop to drain both queued inputs, got sum %d", sum)
+	}
+	if err := p.Enqueue(4); !errors.Is(err, pipeline.ErrPipelineStopped) {
+		t.Errorf("Expected ErrPipelineStopped after Stop, got %v", err)
+	}
+}
+
+func TestPipeline_QueueBlocking(t *testing.T) {
+	results := make(chan pipeline.Result[int], 10)
+	p := pipeline.New[int](pipeline.WithQueue(1, true, func(r pipeline.Result[int]) { results <- r })).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+	if err := p.StartConsumers(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range 5 {
+		if err := p.Enqueue(i); err != nil {
+			t.Fatalf("Expected a blocking Enqueue to wait for room, got %v", err)
+		}
+	}
+	p.Stop(context.Background())
+	if len(results) != 5 {
+		t.Errorf("Expected 5 results, got %d", len(results))
+	}
+
+	if err := pipeline.New[int]().Enqueue(1); !errors.Is(err, pipeline.ErrNoQueue) {
+		t.Errorf("Expected ErrNoQueue without a queue, got %v", err)
+	}
+}
+
+func TestPipeline_QueueStopBeforeStart(t *testing.T) {
+	for _, block := range []bool{false, true} {
+		p := pipeline.New[int](pipeline.WithQueue[int](1, block, nil)).Then(pipeline.Identity[int]())
+		if err := p.Enqueue(1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		blocked := make(chan error, 1)
+		if block {
+			go func() { blocked <- p.Enqueue(2) }()
+		}
+		if err := p.Stop(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := p.Enqueue(3); !errors.Is(err, pipeline.ErrPipelineStopped) {
+			t.Errorf("block=%v: expected ErrPipelineStopped after Stop, got %v", block, err)
+		}
+		if block {
+			select {
+			case err := <-blocked:
+				if !errors.Is(err, pipeline.ErrPipelineStopped) {
+					t.Errorf("Expected the blocked Enqueue to fail with ErrPipelineStopped, got %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Error("Expected Stop to release the blocked Enqueue")
+			}
+		}
+	}
+}
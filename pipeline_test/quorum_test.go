@@ -0,0 +1,56 @@
+// =====================
+// quorum_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Quorum(t *testing.T) {
+	fast := func(ctx context.Context, x int) (int, error) { return x, nil }
+	slow := func(ctx context.Context, x int) (int, error) {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return x * 100, nil
+		}
+	}
+	failing := func(ctx context.Context, x int) (int, error) { return 0, errors.New("replica down") }
+
+	step := pipeline.Quorum(3, sumCombiner, fast, fast, failing, fast, slow)
+	start := time.Now()
+	out, err := step(context.Background(), 2)
+	if err != nil || out != 6 {
+		t.Fatalf("Expected 6 from three fast replicas, got %d (%v)", out, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected Quorum to return without waiting for the slow replica, took %v", elapsed)
+	}
+}
+
+func TestPipeline_QuorumUnreachable(t *testing.T) {
+	ok := func(ctx context.Context, x int) (int, error) { return x, nil }
+	errA := errors.New("a down")
+	errB := errors.New("b down")
+	failA := func(ctx context.Context, x int) (int, error) { return 0, errA }
+	failB := func(ctx context.Context, x int) (int, error) { return 0, errB }
+
+	out, err := pipeline.Quorum(2, sumCombiner, ok, failA, failB)(context.Background(), 7)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Expected both failures joined, got %v", err)
+	}
+	if out != 7 {
+		t.Errorf("Expected the input on failure, got %d", out)
+	}
+
+	if _, err := pipeline.Quorum(4, sumCombiner, ok, ok)(context.Background(), 1); !errors.Is(err, pipeline.ErrQuorumTooLarge) {
+		t.Errorf("Expected ErrQuorumTooLarge when the quorum exceeds the number of steps, got %v", err)
+	}
+}
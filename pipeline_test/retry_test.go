@@ -0,0 +1,141 @@
+// =====================
+// retry_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Retry(t *testing.T) {
+	calls := 0
+	flaky := func(x int) (int, error) {
+		calls++
+		if calls < 3 {
+			return x, errors.New("flaky")
+		}
+		return x * 10, nil
+	}
+	out, err := pipeline.New[int]().Use(pipeline.Retry[int](3, nil)).Then(flaky).Execute(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 20 || calls != 3 {
+		t.Errorf("Expected 20 after 3 calls, got %d after %d", out, calls)
+	}
+}
+
+func TestPipeline_RetryUntil(t *testing.T) {
+	calls := 0
+	poll := func(s string) (string, error) {
+		calls++
+		if calls < 3 {
+			return "pending", nil
+		}
+		return "ready", nil
+	}
+	ready := func(s string) bool { return s == "ready" }
+
+	out, err := pipeline.New[string]().Use(pipeline.RetryUntil[string](5, nil, ready)).Then(poll).Execute("job")
+	if err != nil || out != "ready" || calls != 3 {
+		t.Errorf("Expected ready after 3 calls, got %q (%v) after %d", out, err, calls)
+	}
+
+	calls = 0
+	out, err = pipeline.RetryUntil[string](2, nil, ready)(poll)("job")
+	if !errors.Is(err, pipeline.ErrMaxIterations) || out != "pending" || calls != 2 {
+		t.Errorf("Expected ErrMaxIterations with the last output after 2 calls, got %q (%v) after %d", out, err, calls)
+	}
+
+	calls = 0
+	errFail := errors.New("failure")
+	failing := func(s string) (string, error) { calls++; return s, errFail }
+	if _, err := pipeline.RetryUntil[string](5, nil, ready)(failing)("job"); err != errFail || calls != 1 {
+		t.Errorf("Expected to stop at the first error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestPipeline_RetryContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	failing := func(ctx context.Context, x int) (int, error) {
+		calls++
+		cancel()
+		return x, errors.New("unavailable")
+	}
+	backoff := func(int) time.Duration { return time.Hour }
+	p := pipeline.New[int]().
+		UseContext(pipeline.RetryContext[int](5, backoff)).
+		ThenContext(failing)
+
+	start := time.Now()
+	_, err := p.ExecuteContext(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected backoff to be interrupted")
+	}
+}
+
+func TestPipeline_PlainMiddlewareAroundContextStep(t *testing.T) {
+	type key struct{}
+	var seen []int
+	mw := func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			seen = append(seen, x)
+			return next(x)
+		}
+	}
+	step := func(ctx context.Context, x int) (int, error) {
+		return x + ctx.Value(key{}).(int), nil
+	}
+	p := pipeline.New[int]().Use(mw).
+		UseContext(pipeline.RetryContext[int](1, nil)).
+		ThenContext(step)
+	ctx := context.WithValue(context.Background(), key{}, 5)
+	out, err := p.ExecuteContext(ctx, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != 6 || len(seen) != 1 {
+		t.Errorf("Expected 6 with one middleware call, got %d and %v", out, seen)
+	}
+}
+
+func TestPipeline_ExecuteWithRetry(t *testing.T) {
+	var firstCalls, secondCalls int
+	p := pipeline.New[int]().
+		Then(func(x int) (int, error) { firstCalls++; return x + 1, nil }).
+		Then(func(x int) (int, error) {
+			secondCalls++
+			if secondCalls < 3 {
+				return x, errors.New("transient")
+			}
+			return x * 10, nil
+		})
+
+	out, err := p.ExecuteWithRetry(1, 5, nil)
+	if err != nil || out != 20 {
+		t.Fatalf("Expected 20 from the original input, got %d (%v)", out, err)
+	}
+	if firstCalls != 3 || secondCalls != 3 {
+		t.Errorf("Expected every step to be re-run, got %d and %d calls", firstCalls, secondCalls)
+	}
+
+	failing := pipeline.New[int]().Then(func(x int) (int, error) { return x, errors.New("down") })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := failing.ExecuteWithRetryContext(ctx, 1, 5, func(int) time.Duration { return time.Second }); !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the backoff to stop on cancellation, got %v", err)
+	}
+}
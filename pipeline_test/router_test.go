@@ -0,0 +1,81 @@
+// =====================
+// router_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_WeightedRouter(t *testing.T) {
+	routes := map[string]pipeline.StepFunc[int]{
+		"control":    pipeline.Wrap(func(x int) int { return 0 }),
+		"experiment": pipeline.Wrap(func(x int) int { return 1 }),
+	}
+	step := pipeline.WeightedRouter(map[string]int{"control": 9, "experiment": 1}, routes)
+	hits := 0
+	for i := 0; i < 10000; i++ {
+		out, err := step(i)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		hits += out
+	}
+	if hits < 700 || hits > 1300 {
+		t.Errorf("Expected roughly 10%% experiment traffic, got %d of 10000", hits)
+	}
+
+	only := pipeline.WeightedRouter(map[string]int{"control": 0, "experiment": 3}, routes)
+	if out, _ := only(5); out != 1 {
+		t.Errorf("Expected zero-weight route to be skipped, got %d", out)
+	}
+	none := pipeline.WeightedRouter(map[string]int{"missing": 1}, routes)
+	if _, err := none(5); !errors.Is(err, pipeline.ErrNoRoute) {
+		t.Errorf("Expected ErrNoRoute, got %v", err)
+	}
+}
+
+func TestPipeline_StickyWeightedRouter(t *testing.T) {
+	routes := map[string]pipeline.StepFunc[int]{
+		"a": pipeline.Wrap(func(x int) int { return 0 }),
+		"b": pipeline.Wrap(func(x int) int { return 1 }),
+	}
+	step := pipeline.StickyWeightedRouter(map[string]int{"a": 1, "b": 1}, routes, func(x int) string {
+		return fmt.Sprint(x % 10)
+	})
+	for i := 0; i < 100; i++ {
+		first, _ := step(i)
+		again, _ := step(i + 10)
+		if first != again {
+			t.Fatalf("Expected inputs with the same key to share a route")
+		}
+	}
+}
+
+func TestPipeline_WithRandSource(t *testing.T) {
+	routes := map[string]pipeline.StepFunc[int]{
+		"control":    pipeline.Wrap(func(x int) int { return 0 }),
+		"experiment": pipeline.Wrap(func(x int) int { return 1 }),
+	}
+	run := func() string {
+		p := pipeline.New[int](pipeline.WithRandSource[int](rand.NewPCG(1, 2))).
+			ThenContext(pipeline.WeightedRouterContext(map[string]int{"control": 1, "experiment": 1}, routes))
+		var routed []int
+		for i := 0; i < 20; i++ {
+			out, err := p.Execute(i)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			routed = append(routed, out)
+		}
+		return fmt.Sprint(routed)
+	}
+	if first, second := run(), run(); first != second {
+		t.Errorf("Expected the same routes from the same seed, got %s and %s", first, second)
+	}
+}
@@ -0,0 +1,145 @@
+// =====================
+// stats_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_ErrorStats(t *testing.T) {
+	errOdd := errors.New("odd")
+	p := pipeline.New[int](pipeline.WithErrorStats[int]()).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		ThenNamed("even-only", func(x int) (int, error) {
+			if x%2 != 0 {
+				return x, errOdd
+			}
+			return x, nil
+		})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(in int) {
+			defer wg.Done()
+			p.Execute(in)
+		}(i)
+	}
+	wg.Wait()
+
+	stats := p.ErrorStats()
+	if stats["even-only"] != 5 {
+		t.Errorf("Expected 5 failures for even-only, got %d", stats["even-only"])
+	}
+	if _, ok := stats["step-0"]; ok {
+		t.Errorf("Expected no failures recorded for step-0, got %v", stats)
+	}
+}
+
+func TestPipeline_ErrorStatsDisabled(t *testing.T) {
+	p := pipeline.New[int]().
+		Then(func(x int) (int, error) { return x, errors.New("fail") })
+	p.Execute(1)
+	if stats := p.ErrorStats(); len(stats) != 0 {
+		t.Errorf("Expected empty stats, got %v", stats)
+	}
+}
+
+func TestPipeline_StepLatencyPercentile(t *testing.T) {
+	p := pipeline.New[int](pipeline.WithLatencyHistogram[int]()).
+		ThenNamed("lookup", func(x int) (int, error) {
+			if x%10 == 0 {
+				time.Sleep(20 * time.Millisecond)
+			} else {
+				time.Sleep(time.Millisecond)
+			}
+			return x, nil
+		})
+	for i := 1; i <= 50; i++ {
+		p.Execute(i)
+	}
+	p50 := p.StepLatencyPercentile("lookup", 0.5)
+	p99 := p.StepLatencyPercentile("lookup", 0.99)
+	if p50 < time.Millisecond || p50 > 10*time.Millisecond {
+		t.Errorf("Expected p50 around 1ms, got %v", p50)
+	}
+	if p99 < 18*time.Millisecond {
+		t.Errorf("Expected p99 around 20ms, got %v", p99)
+	}
+	if d := p.StepLatencyPercentile("missing", 0.5); d != 0 {
+		t.Errorf("Expected 0 for unknown step, got %v", d)
+	}
+}
+
+func TestPipeline_MiddlewareOverhead(t *testing.T) {
+	slowMiddleware := func(next pipeline.StepFunc[int]) pipeline.StepFunc[int] {
+		return func(x int) (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return next(x)
+		}
+	}
+	step := func(x int) (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return x + 1, nil
+	}
+	p := pipeline.New[int](pipeline.WithMiddlewareOverhead[int]()).
+		Use(slowMiddleware).
+		ThenSkipMiddleware(step).
+		Then(step)
+
+	for range 3 {
+		if _, err := p.Execute(0); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if d := p.MiddlewareOverhead("step-1"); d < 15*time.Millisecond || d > 40*time.Millisecond {
+		t.Errorf("Expected about 20ms of overhead for the wrapped step, got %v", d)
+	}
+	if d := p.MiddlewareOverhead("step-0"); d > 5*time.Millisecond {
+		t.Errorf("Expected negligible overhead for the bare step, got %v", d)
+	}
+	if d := pipeline.New[int]().Then(step).MiddlewareOverhead("step-0"); d != 0 {
+		t.Errorf("Expected 0 when measurement is disabled, got %v", d)
+	}
+}
+
+func TestPipeline_FailureRecorder(t *testing.T) {
+	broken := true
+	p := pipeline.New[int](pipeline.WithFailureRecorder[int](2)).
+		Then(pipeline.Wrap(func(x int) int { return x + 1 })).
+		Then(func(x int) (int, error) {
+			if broken && x%2 == 0 {
+				return x, errors.New("downstream unavailable")
+			}
+			return x * 10, nil
+		})
+
+	for _, in := range []int{1, 2, 3, 5} {
+		p.Execute(in)
+	}
+	failures := p.FailureRecorder().Failures()
+	if len(failures) != 2 {
+		t.Fatalf("Expected the 2 most recent failures, got %+v", failures)
+	}
+	if f := failures[0]; f.Input != 3 || f.Step != 1 || f.Err == nil {
+		t.Errorf("Unexpected failure record %+v", f)
+	}
+
+	broken = false
+	outs, errs := p.FailureRecorder().Replay()
+	if len(outs) != 2 || outs[0] != 40 || outs[1] != 60 || errs[0] != nil || errs[1] != nil {
+		t.Errorf("Expected replayed outputs [40 60], got %v (%v)", outs, errs)
+	}
+	if n := len(p.FailureRecorder().Failures()); n != 0 {
+		t.Errorf("Expected replay to clear the recorded failures, got %d", n)
+	}
+	if pipeline.New[int]().FailureRecorder().Failures() != nil {
+		t.Errorf("Expected no recorder without WithFailureRecorder")
+	}
+}
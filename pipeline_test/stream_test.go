@@ -0,0 +1,283 @@
+// =====================
+// stream_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// feed returns a closed channel holding values.
+func feed[T any](values ...T) <-chan T {
+	ch := make(chan T, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+// drain collects every result from ch.
+func drain[T any](ch <-chan pipeline.Result[T]) []pipeline.Result[T] {
+	var out []pipeline.Result[T]
+	for r := range ch {
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestPipeline_WindowParallel(t *testing.T) {
+	ctx := context.Background()
+	steps := []pipeline.StepFunc[int]{
+		pipeline.Wrap(func(x int) int { return x }),
+		pipeline.Wrap(func(x int) int { return x * 10 }),
+	}
+	stage := pipeline.WindowParallel(2, steps, pipeline.SumCombiner[int])
+	got := drain(stage(ctx, pipeline.ToResults(ctx, feed(1, 2, 3, 4, 5))))
+	expected := []int{33, 77, 55}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d windows, got %v", len(expected), got)
+	}
+	for i, r := range got {
+		if r.Err != nil || r.Value != expected[i] {
+			t.Errorf("Window %d: expected %d, got %d (%v)", i, expected[i], r.Value, r.Err)
+		}
+	}
+}
+
+func TestPipeline_WindowParallelErrors(t *testing.T) {
+	ctx := context.Background()
+	errUpstream := errors.New("upstream")
+	errStep := errors.New("step")
+	in := make(chan pipeline.Result[int], 3)
+	in <- pipeline.Result[int]{Err: errUpstream}
+	in <- pipeline.Result[int]{Value: 1}
+	in <- pipeline.Result[int]{Value: -1}
+	close(in)
+	steps := []pipeline.StepFunc[int]{func(x int) (int, error) {
+		if x < 0 {
+			return x, errStep
+		}
+		return x, nil
+	}}
+	got := drain(pipeline.WindowParallel(2, steps, pipeline.SumCombiner[int])(ctx, in))
+	if len(got) != 2 || got[0].Err != errUpstream || got[1].Err != errStep {
+		t.Errorf("Expected forwarded and step errors, got %v", got)
+	}
+}
+
+func TestPipeline_Coalesce(t *testing.T) {
+	ctx := context.Background()
+	// Accumulate partial updates until a "!" commit marker.
+	stage := pipeline.Coalesce(func(prev, curr string) (string, bool) {
+		merged := prev + curr
+		return merged, curr != "!"
+	})
+	in := make(chan pipeline.Result[string], 8)
+	errFail := errors.New("failure")
+	for _, r := range []pipeline.Result[string]{
+		{Value: "a"}, {Value: "b"}, {Value: "!"},
+		{Value: "c"}, {Err: errFail},
+		{Value: "d"}, {Value: "e"},
+	} {
+		in <- r
+	}
+	close(in)
+
+	got := drain(stage(ctx, in))
+	expected := []pipeline.Result[string]{
+		{Value: "ab!"}, {Value: "c"}, {Err: errFail}, {Value: "de"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i].Value != expected[i].Value || !errors.Is(got[i].Err, expected[i].Err) {
+			t.Errorf("Result %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestPipeline_Fold(t *testing.T) {
+	sum := func(acc int, v int) int { return acc + v }
+	total, err := pipeline.Fold(context.Background(), feed(1, 2, 3, 4), 10, sum)
+	if err != nil || total != 20 {
+		t.Errorf("Expected 20, got %d (%v)", total, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	go func() {
+		in <- 5
+		cancel()
+	}()
+	total, err = pipeline.Fold(ctx, in, 0, sum)
+	if !errors.Is(err, context.Canceled) || total != 5 {
+		t.Errorf("Expected (5, context.Canceled), got (%d, %v)", total, err)
+	}
+}
+
+func TestPipeline_MapReduceStream(t *testing.T) {
+	ctx := context.Background()
+	errFail := errors.New("failure")
+	in := make(chan pipeline.Result[int], 8)
+	for _, r := range []pipeline.Result[int]{
+		{Value: 1}, {Value: 2}, {Value: 3}, {Value: 4},
+		{Value: 5}, {Err: errFail}, {Value: 6}, {Value: 7},
+	} {
+		in <- r
+	}
+	close(in)
+
+	square := func(x int) int { return x * x }
+	sum := func(xs []int) int {
+		total := 0
+		for _, x := range xs {
+			total += x
+		}
+		return total
+	}
+	got := drain(pipeline.MapReduceStream(2, 3, square, sum)(ctx, in))
+	expected := []pipeline.Result[int]{
+		{Value: 5}, {Value: 25}, {Value: 25}, {Err: errFail}, {Value: 85},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i].Value != expected[i].Value || got[i].Err != expected[i].Err {
+			t.Errorf("Result %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestPipeline_ErrorRateBreaker(t *testing.T) {
+	boom := errors.New("boom")
+	breaker := pipeline.ErrorRateBreaker[int](time.Second, 0.5)
+	in := feed(
+		pipeline.Result[int]{Value: 1},
+		pipeline.Result[int]{Value: 2, Err: boom},
+		pipeline.Result[int]{Value: 3, Err: boom},
+		pipeline.Result[int]{Value: 4},
+	)
+	got := drain(breaker.Stage()(context.Background(), in))
+	want := []error{nil, boom, pipeline.ErrCircuitOpen, nil}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d results, got %v", len(want), got)
+	}
+	for i, r := range got {
+		if !errors.Is(r.Err, want[i]) || (want[i] == nil && r.Err != nil) || r.Value != i+1 {
+			t.Errorf("Result %d: expected value %d with %v, got %+v", i, i+1, want[i], r)
+		}
+	}
+	if rate := breaker.Rate(); rate != 0.5 || breaker.Open() {
+		t.Errorf("Expected a closed breaker at rate 0.5, got %v", rate)
+	}
+
+	short := pipeline.ErrorRateBreaker[int](20*time.Millisecond, 0.5)
+	drain(short.Stage()(context.Background(), feed(pipeline.Result[int]{Err: boom})))
+	if !short.Open() {
+		t.Error("Expected the breaker to open")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if short.Open() || short.Rate() != 0 {
+		t.Errorf("Expected the breaker to recover once the window passed, got rate %v", short.Rate())
+	}
+}
+
+func TestPipeline_Partition(t *testing.T) {
+	routes := map[string]func(int) bool{
+		"even":  func(x int) bool { return x%2 == 0 },
+		"small": func(x int) bool { return x < 3 },
+	}
+	for _, drop := range []bool{false, true} {
+		outs := pipeline.Partition(context.Background(), feed(1, 2, 3, 4, 5), routes, drop)
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			got = map[string][]int{}
+		)
+		for name, ch := range outs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for v := range ch {
+					mu.Lock()
+					got[name] = append(got[name], v)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		want := "map[:[3 5] even:[2 4] small:[1]]"
+		if drop {
+			want = "map[even:[2 4] small:[1]]"
+		}
+		if fmt.Sprint(got) != want || len(outs) != strings.Count(want, ":[") {
+			t.Errorf("drop=%v: expected %s, got %v", drop, want, got)
+		}
+	}
+}
+
+func TestPipeline_PartitionEmptyRouteName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a route named \"\" to panic")
+		}
+	}()
+	pipeline.Partition(context.Background(), feed(1), map[string]func(int) bool{"": func(int) bool { return true }}, true)
+}
+
+func TestPipeline_PartitionCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	outs := pipeline.Partition(ctx, in, map[string]func(int) bool{"all": func(int) bool { return true }}, false)
+	cancel()
+	for name, ch := range outs {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("Expected no values on %q", name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %q to be closed after cancellation with the input still open", name)
+		}
+	}
+}
+
+func TestPipeline_StreamStagesCloseOnCancel(t *testing.T) {
+	stages := map[string]pipeline.StreamStage[int]{
+		"WindowParallel": pipeline.WindowParallel(2, []pipeline.StepFunc[int]{pipeline.Identity[int]()}, sumCombiner),
+		"Coalesce":       pipeline.Coalesce(func(prev, curr int) (int, bool) { return prev + curr, true }),
+		"RateBreaker":    pipeline.ErrorRateBreaker[int](time.Second, 0.5).Stage(),
+		"MapReduce":      pipeline.MapReduceStream(2, 1, func(x int) int { return x }, func(xs []int) int { return len(xs) }),
+		"ToResults": func(ctx context.Context, _ <-chan pipeline.Result[int]) <-chan pipeline.Result[int] {
+			return pipeline.ToResults(ctx, make(chan int))
+		},
+	}
+	for name, stage := range stages {
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan pipeline.Result[int], 1)
+		in <- pipeline.Result[int]{Value: 1}
+		out := stage(ctx, in)
+		cancel()
+		done := make(chan struct{})
+		go func() {
+			drain(out)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("%s: expected the output to close after cancellation with the input open", name)
+		}
+	}
+}
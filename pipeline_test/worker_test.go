@@ -0,0 +1,83 @@
+// =====================
+// worker_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Worker(t *testing.T) {
+	p := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x * 2 }))
+	in := make(chan int)
+	out := make(chan pipeline.Result[int], 100)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Worker(context.Background(), in, out)
+		}()
+	}
+	for i := 1; i <= 100; i++ {
+		in <- i
+	}
+	close(in)
+	wg.Wait()
+	close(out)
+	sum := 0
+	for r := range out {
+		if r.Err != nil {
+			t.Fatalf("Unexpected error: %v", r.Err)
+		}
+		sum += r.Value
+	}
+	if sum != 10100 {
+		t.Errorf("Expected 10100, got %d", sum)
+	}
+}
+
+func TestPipeline_WorkerStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pipeline.New[int]().Worker(ctx, make(chan int), make(chan pipeline.Result[int]))
+		close(done)
+	}()
+	cancel()
+	<-done
+}
+
+func TestPipeline_ExecuteChan(t *testing.T) {
+	p := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+	ch := p.ExecuteChan(context.Background(), 1)
+	r := <-ch
+	if r.Err != nil || r.Value != 2 {
+		t.Errorf("Expected 2, got %d (%v)", r.Value, r.Err)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected channel to be closed after the result")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := pipeline.New[int]().ThenContext(func(ctx context.Context, x int) (int, error) {
+		<-ctx.Done()
+		return x, ctx.Err()
+	})
+	ch = slow.ExecuteChan(ctx, 1)
+	select {
+	case <-ch:
+		t.Fatalf("Expected execution to still be running")
+	case <-time.After(5 * time.Millisecond):
+	}
+	cancel()
+	if r := <-ch; !errors.Is(r.Err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", r.Err)
+	}
+}
@@ -0,0 +1,51 @@
+// =====================
+// zip_test.go
+// =====================
+package pipeline_test_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+func TestPipeline_Zip(t *testing.T) {
+	pa := pipeline.New[int]().Then(pipeline.Wrap(func(x int) int { return x + 1 }))
+	pb := pipeline.New[string]().Then(pipeline.Wrap(strings.ToUpper))
+	p := pipeline.New[pipeline.Pair[int, string]]().ThenContext(pipeline.Zip(pa, pb))
+
+	out, err := p.Execute(pipeline.Pair[int, string]{A: 1, B: "go"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.A != 2 || out.B != "GO" {
+		t.Errorf("Expected {2 GO}, got %+v", out)
+	}
+}
+
+func TestPipeline_ZipCancelsOnFailure(t *testing.T) {
+	boom := errors.New("boom")
+	pa := pipeline.New[int]().Then(func(x int) (int, error) { return x, boom })
+	pb := pipeline.New[string]().ThenContext(func(ctx context.Context, s string) (string, error) {
+		select {
+		case <-ctx.Done():
+			return s, ctx.Err()
+		case <-time.After(time.Second):
+			return s, nil
+		}
+	})
+
+	input := pipeline.Pair[int, string]{A: 1, B: "go"}
+	start := time.Now()
+	out, err := pipeline.Zip(pa, pb)(context.Background(), input)
+	if !errors.Is(err, boom) || out != input {
+		t.Errorf("Expected the input with boom, got %+v, %v", out, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the other pipeline to be cancelled, took %v", elapsed)
+	}
+}
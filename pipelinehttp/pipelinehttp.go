@@ -0,0 +1,75 @@
+// Package pipelinehttp exposes a pipeline as an http.Handler.
+package pipelinehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// ErrorMapper chooses the HTTP status code for an error returned by the pipeline.
+type ErrorMapper func(error) int
+
+// Option configures a Handler.
+type Option func(*config)
+
+type config struct {
+	mapError ErrorMapper
+}
+
+// WithErrorMapper replaces DefaultErrorMapper for choosing error status codes.
+func WithErrorMapper(m ErrorMapper) Option {
+	return func(c *config) {
+		c.mapError = m
+	}
+}
+
+// DefaultErrorMapper maps the package's sentinel errors to matching status codes:
+// timeouts to 504, rate limiting to 429, open circuits to 503 and oversized values to
+// 413. Any other error maps to 500.
+func DefaultErrorMapper(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, pipeline.ErrStepTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, pipeline.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, pipeline.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, pipeline.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Handler returns an http.Handler that decodes each request into T, runs p with the
+// request's context through ExecuteContext, and encodes the result. A decode error
+// responds 400; a pipeline error responds with the status chosen by the error mapper.
+// Error responses carry only the status text so internal details are not leaked.
+func Handler[T any](p *pipeline.Pipeline[T], decode func(*http.Request) (T, error), encode func(http.ResponseWriter, T) error, opts ...Option) http.Handler {
+	cfg := config{mapError: DefaultErrorMapper}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		input, err := decode(r)
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest)
+			return
+		}
+		out, err := p.ExecuteContext(r.Context(), input)
+		if err != nil {
+			writeStatus(w, cfg.mapError(err))
+			return
+		}
+		if err := encode(w, out); err != nil {
+			writeStatus(w, http.StatusInternalServerError)
+		}
+	})
+}
+
+func writeStatus(w http.ResponseWriter, code int) {
+	http.Error(w, http.StatusText(code), code)
+}
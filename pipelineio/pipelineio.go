@@ -0,0 +1,67 @@
+// Package pipelineio runs pipelines over data read from an io.Reader.
+package pipelineio
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// Option configures StreamLines.
+type Option func(*config)
+
+type config struct {
+	maxLine int
+}
+
+// WithMaxLineSize sets the longest line StreamLines accepts, in bytes, replacing the
+// bufio.Scanner default of 64KiB. A longer line stops the stream with
+// bufio.ErrTooLong.
+func WithMaxLineSize(n int) Option {
+	return func(c *config) {
+		c.maxLine = n
+	}
+}
+
+// StreamLines reads r line by line and runs each line, without its line ending, through
+// p with ctx, emitting one Result per line in order, so log files and NDJSON streams can
+// be processed as they are read. A failing line does not stop the stream. A read error
+// is emitted as a final Result carrying it. The channel is closed at the end of r, after
+// a read error, or once ctx is done; a read already blocked in r is not interrupted.
+func StreamLines(ctx context.Context, r io.Reader, p *pipeline.Pipeline[string], opts ...Option) <-chan pipeline.Result[string] {
+	cfg := config{maxLine: bufio.MaxScanTokenSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	out := make(chan pipeline.Result[string])
+	go func() {
+		defer close(out)
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, min(cfg.maxLine, 4096)), cfg.maxLine)
+		for sc.Scan() {
+			if ctx.Err() != nil {
+				return
+			}
+			v, err := p.ExecuteContext(ctx, sc.Text())
+			if !send(ctx, out, pipeline.Result[string]{Value: v, Err: err}) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			send(ctx, out, pipeline.Result[string]{Err: err})
+		}
+	}()
+	return out
+}
+
+// send delivers v on out unless ctx is done first, reporting whether it was sent.
+func send(ctx context.Context, out chan<- pipeline.Result[string], v pipeline.Result[string]) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+// Package pipelinetest provides helpers for testing pipelines: assertions for common
+// properties and a harness for driving testing/quick or fuzz inputs through a pipeline.
+//
+// The helpers compare outputs with reflect.DeepEqual, so they are only meaningful for
+// deterministic pipelines. Use SequentialParallel instead of Parallel in pipelines
+// under test when the combined result depends on execution order.
+package pipelinetest
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// Invariant reports whether out and err are acceptable results for input. A non-nil
+// return value describes the violation.
+type Invariant[T any] func(input, out T, err error) error
+
+// AssertPure runs p twice on input and fails t unless both runs produce equal outputs
+// and errors.
+func AssertPure[T any](t testing.TB, p *pipeline.Pipeline[T], input T) {
+	t.Helper()
+	out1, err1 := p.Execute(input)
+	out2, err2 := p.Execute(input)
+	if !reflect.DeepEqual(out1, out2) || !sameError(err1, err2) {
+		t.Errorf("pipeline is not pure for input %v: got (%v, %v) then (%v, %v)", input, out1, err1, out2, err2)
+	}
+}
+
+// AssertIdempotent fails t unless running p on its own output yields that output again,
+// i.e. p(p(input)) == p(input).
+func AssertIdempotent[T any](t testing.TB, p *pipeline.Pipeline[T], input T) {
+	t.Helper()
+	once, err := p.Execute(input)
+	if err != nil {
+		t.Errorf("pipeline failed for input %v: %v", input, err)
+		return
+	}
+	twice, err := p.Execute(once)
+	if err != nil {
+		t.Errorf("pipeline failed when re-applied to %v: %v", once, err)
+		return
+	}
+	if !reflect.DeepEqual(once, twice) {
+		t.Errorf("pipeline is not idempotent for input %v: got %v then %v", input, once, twice)
+	}
+}
+
+// AssertInvariant runs p on input and fails t if invariant reports a violation.
+// It is intended for use inside fuzz targets.
+func AssertInvariant[T any](t testing.TB, p *pipeline.Pipeline[T], input T, invariant Invariant[T]) {
+	t.Helper()
+	out, err := p.Execute(input)
+	if violation := invariant(input, out, err); violation != nil {
+		t.Errorf("invariant violated for input %v: %v", input, violation)
+	}
+}
+
+// Property adapts an invariant into a function suitable for quick.Check.
+func Property[T any](p *pipeline.Pipeline[T], invariant Invariant[T]) func(T) bool {
+	return func(input T) bool {
+		out, err := p.Execute(input)
+		return invariant(input, out, err) == nil
+	}
+}
+
+// Check drives inputs generated by testing/quick through p and fails t on the first
+// input that violates invariant. A nil cfg uses quick's defaults.
+func Check[T any](t testing.TB, p *pipeline.Pipeline[T], invariant Invariant[T], cfg *quick.Config) {
+	t.Helper()
+	if err := quick.Check(Property(p, invariant), cfg); err != nil {
+		t.Errorf("property check failed: %v", err)
+	}
+}
+
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Error() == b.Error()
+}
@@ -0,0 +1,65 @@
+package pipelinetest
+
+import (
+	"sync"
+
+	"github.com/TheOrchestraX/pipeline"
+)
+
+// Spy records the values passed into and produced by each step of a pipeline, for
+// asserting on intermediate values directly. It is safe for concurrent use.
+type Spy[T any] struct {
+	mu      sync.Mutex
+	inputs  map[int][]T
+	outputs map[int][]T
+}
+
+// NewSpy installs a Spy on p as a middleware. Installed after p's other middlewares, it
+// is innermost and sees exactly the values each step is called with and returns. It
+// does not change what the pipeline computes, but steps added with ThenSkipMiddleware
+// are not recorded.
+func NewSpy[T any](p *pipeline.Pipeline[T]) *Spy[T] {
+	s := &Spy[T]{inputs: make(map[int][]T), outputs: make(map[int][]T)}
+	p.UseFactoryNamed("pipelinetest.spy", func(info pipeline.StepInfo) pipeline.Middleware[T] {
+		return func(next pipeline.StepFunc[T]) pipeline.StepFunc[T] {
+			return func(input T) (T, error) {
+				s.record(s.inputs, info.Index, input)
+				out, err := next(input)
+				if err == nil {
+					s.record(s.outputs, info.Index, out)
+				}
+				return out, err
+			}
+		}
+	})
+	return s
+}
+
+func (s *Spy[T]) record(values map[int][]T, step int, v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values[step] = append(values[step], v)
+}
+
+// Inputs returns the values the step at stepIndex was called with, in call order.
+func (s *Spy[T]) Inputs(stepIndex int) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]T(nil), s.inputs[stepIndex]...)
+}
+
+// Outputs returns the outputs of the successful calls of the step at stepIndex, in call
+// order.
+func (s *Spy[T]) Outputs(stepIndex int) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]T(nil), s.outputs[stepIndex]...)
+}
+
+// Reset forgets every recorded value.
+func (s *Spy[T]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.inputs)
+	clear(s.outputs)
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+// Branch is a step that chooses between named branches and can report its choice for
+// an input without running anything, which Plan uses to preview routing.
+type Branch[T any] interface {
+	// Execute runs the chosen branch on input.
+	Execute(input T) (T, error)
+	// Choose returns the name of the branch Execute would run for input, or "" if it
+	// would pass the input through. It must not have side effects.
+	Choose(input T) string
+}
+
+type namedConditional[T any] struct {
+	predicate          func(T) bool
+	thenName, elseName string
+	thenStep, elseStep StepFunc[T]
+}
+
+// NamedConditional is a Conditional whose branches are named, so that Plan can report
+// which one an input would take. A nil elseStep passes the input through unchanged,
+// like ConditionalThen.
+func NamedConditional[T any](predicate func(T) bool, thenName string, thenStep StepFunc[T], elseName string, elseStep StepFunc[T]) Branch[T] {
+	return &namedConditional[T]{
+		predicate: predicate,
+		thenName:  thenName,
+		thenStep:  thenStep,
+		elseName:  elseName,
+		elseStep:  elseStep,
+	}
+}
+
+func (c *namedConditional[T]) Execute(input T) (T, error) {
+	if c.predicate(input) {
+		return c.thenStep(input)
+	}
+	if c.elseStep == nil {
+		return input, nil
+	}
+	return c.elseStep(input)
+}
+
+func (c *namedConditional[T]) Choose(input T) string {
+	if c.predicate(input) {
+		return c.thenName
+	}
+	if c.elseStep == nil {
+		return ""
+	}
+	return c.elseName
+}
+
+// ThenBranch appends a Branch as a step, applying any registered Middleware. Plan
+// reports the branch it would take rather than just the step's name.
+func (p *Pipeline[T]) ThenBranch(b Branch[T]) *Pipeline[T] {
+	return p.add(stage[T]{choose: b.Choose}, b.Execute, nil)
+}
+
+// Plan reports, without running any step, the names of the steps an execution would
+// run for input, in order. Steps added with ThenBranch are reported as "step/branch",
+// or omitted if the branch would pass the input through. The pre-steps, including the
+// WithDefaultInput substitution, are applied first, so they must be free of side
+// effects. Since no step runs, each Branch chooses based on that input rather than on
+// the output of earlier steps, so Plan is exact only for routing decided on the
+// original input. It fails with ErrEmptyPipeline and ErrInvalidInput like the Execute
+// methods.
+func (p *Pipeline[T]) Plan(input T) ([]string, error) {
+	steps := p.stages()
+	if len(steps) == 0 && p.requireSteps {
+		return nil, ErrEmptyPipeline
+	}
+	input, err := p.prepare(input)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(steps))
+	for _, s := range steps {
+		if s.choose == nil {
+			names = append(names, s.name)
+			continue
+		}
+		if branch := s.choose(input); branch != "" {
+			names = append(names, s.name+"/"+branch)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// queue is a bounded work queue drained through the pipeline by consumer goroutines.
+// It is registered as a Service, so stopping the pipeline drains it.
+type queue[T any] struct {
+	ch    chan T
+	block bool
+	sink  func(Result[T])
+	done  chan struct{} // closed when Stop begins, releasing blocked Enqueue calls
+	once  sync.Once
+	mu    sync.RWMutex // held for reading by Enqueue, for writing to close ch
+	shut  bool
+	wg    sync.WaitGroup
+}
+
+func (q *queue[T]) Start() error { return nil }
+
+// shutdown stops accepting inputs and releases blocked Enqueue calls. It is safe to
+// call more than once, and on a nil queue.
+func (q *queue[T]) shutdown() {
+	if q == nil {
+		return
+	}
+	q.once.Do(func() {
+		close(q.done)
+		q.mu.Lock()
+		q.shut = true
+		close(q.ch)
+		q.mu.Unlock()
+	})
+}
+
+// Stop stops accepting inputs and waits until the consumers have processed every
+// queued one, or until ctx is done.
+func (q *queue[T]) Stop(ctx context.Context) error {
+	q.shutdown()
+	drained := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WithQueue gives the pipeline a bounded work queue of the given capacity, filled by
+// Enqueue and drained by the consumers StartConsumers launches. Each result is passed
+// to sink, which may be nil and must be safe for concurrent use with more than one
+// consumer. When the queue is full, Enqueue blocks if block is set and otherwise fails
+// with ErrQueueFull. Stop drains the queue before returning; if the consumers were
+// never started, it only shuts the queue, dropping the queued inputs.
+func WithQueue[T any](capacity int, block bool, sink func(Result[T])) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.queue = &queue[T]{ch: make(chan T, capacity), block: block, sink: sink, done: make(chan struct{})}
+		p.lifecycle.services = append(p.lifecycle.services, p.queue)
+	}
+}
+
+// Enqueue adds input to the pipeline's queue for a consumer to execute. It returns
+// ErrQueueFull if the queue is full and not blocking, ErrNoQueue if the pipeline was
+// created without WithQueue, and ErrPipelineStopped once Stop has been called.
+func (p *Pipeline[T]) Enqueue(input T) error {
+	q := p.queue
+	if q == nil {
+		return ErrNoQueue
+	}
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.shut {
+		return ErrPipelineStopped
+	}
+	if !q.block {
+		select {
+		case q.ch <- input:
+			return nil
+		default:
+			return ErrQueueFull
+		}
+	}
+	select {
+	case q.ch <- input:
+		return nil
+	case <-q.done:
+		return ErrPipelineStopped
+	}
+}
+
+// StartConsumers starts the pipeline, as Start does, and launches n goroutines (at
+// least one) that execute queued inputs and pass each Result to the queue's sink. For
+// a pipeline created without WithQueue it only starts the pipeline.
+func (p *Pipeline[T]) StartConsumers(n int) error {
+	if err := p.Start(); err != nil {
+		return err
+	}
+	q := p.queue
+	if q == nil {
+		return nil
+	}
+	q.wg.Add(max(1, n))
+	for range max(1, n) {
+		go func() {
+			defer q.wg.Done()
+			for input := range q.ch {
+				v, err := p.ExecuteContext(context.Background(), input)
+				if q.sink != nil {
+					q.sink(Result[T]{Value: v, Err: err})
+				}
+			}
+		}()
+	}
+	return nil
+}
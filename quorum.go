@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Quorum runs multiple StepFuncContexts on the same input concurrently and returns as
+// soon as n of them succeed, cancelling the rest. The combiner receives the first n
+// successful outputs in the order they completed; a nil combiner returns the input. Once
+// so many steps have failed that n successes are no longer possible, the input is
+// returned with the failures joined. If ctx is done first, ctx.Err() is returned. If n
+// exceeds the number of steps, every call fails with ErrQuorumTooLarge.
+func Quorum[T any](n int, combiner func([]T) (T, error), steps ...StepFuncContext[T]) StepFuncContext[T] {
+	type outcome struct {
+		out T
+		err error
+	}
+	return func(ctx context.Context, input T) (T, error) {
+		if n > len(steps) {
+			return input, fmt.Errorf("%w: quorum of %d with %d steps", ErrQuorumTooLarge, n, len(steps))
+		}
+		branchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		// Buffered so branches still running after the quorum is reached never block.
+		outcomes := make(chan outcome, len(steps))
+		for _, step := range steps {
+			go func(s StepFuncContext[T]) {
+				out, err := s(branchCtx, input)
+				outcomes <- outcome{out, err}
+			}(step)
+		}
+		var (
+			results []T
+			failed  []error
+		)
+		for len(results) < n {
+			select {
+			case <-ctx.Done():
+				return input, ctx.Err()
+			case o := <-outcomes:
+				if o.err != nil {
+					failed = append(failed, o.err)
+					if len(failed) > len(steps)-n {
+						return input, errors.Join(failed...)
+					}
+					continue
+				}
+				results = append(results, o.out)
+			}
+		}
+		if combiner == nil {
+			return input, nil
+		}
+		return combiner(results)
+	}
+}
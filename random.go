@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+)
+
+// lockedSource makes a rand.Source safe for concurrent use.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+// WithRandSource makes the pipeline's context-aware randomized combinators, such as
+// WeightedRouterContext, draw from src instead of the global generator, so tests of
+// random routing are reproducible. Plain steps cannot see the source: WeightedRouter
+// added with Then keeps using the global generator, so use WeightedRouterContext with
+// ThenContext instead. The pipeline serializes access to src, which need not
+// be safe for concurrent use; results stay reproducible as long as calls are made one at
+// a time.
+func WithRandSource[T any](src rand.Source) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.rng = rand.New(&lockedSource{src: src})
+	}
+}
+
+type randKey struct{}
+
+// withRand returns ctx carrying rng, if it is set.
+func withRand(ctx context.Context, rng *rand.Rand) context.Context {
+	if rng == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, randKey{}, rng)
+}
+
+// randIntN returns a random number in [0, n) from the generator carried by ctx, or from
+// the global generator if there is none.
+func randIntN(ctx context.Context, n int) int {
+	if rng, ok := ctx.Value(randKey{}).(*rand.Rand); ok {
+		return rng.IntN(n)
+	}
+	return rand.IntN(n)
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateSample is one result seen by a RateBreaker.
+type rateSample struct {
+	at     time.Time
+	failed bool
+}
+
+// RateBreaker is a stream circuit breaker driven by the error rate over a sliding time
+// window, created by ErrorRateBreaker. Its Stage is used in the stream and its Rate and
+// Open methods report its state; they are safe to call concurrently with the stage.
+type RateBreaker[T any] struct {
+	window    time.Duration
+	threshold float64
+
+	mu       sync.Mutex
+	samples  []rateSample
+	failures int
+}
+
+// ErrorRateBreaker creates a RateBreaker that opens while more than threshold, a
+// fraction between 0 and 1, of the results seen in the last window were errors.
+func ErrorRateBreaker[T any](window time.Duration, threshold float64) *RateBreaker[T] {
+	return &RateBreaker[T]{window: window, threshold: threshold}
+}
+
+// Stage returns the breaker as a StreamStage. Each result is counted, then forwarded
+// unchanged while the breaker is closed; while it is open, every result is replaced by
+// one carrying its value and ErrCircuitOpen, so downstream stages are spared until
+// enough of the upstream errors have left the window.
+func (b *RateBreaker[T]) Stage() StreamStage[T] {
+	return func(ctx context.Context, in <-chan Result[T]) <-chan Result[T] {
+		out := make(chan Result[T])
+		go func() {
+			defer close(out)
+			for r, ok := receive(ctx, in); ok; r, ok = receive(ctx, in) {
+				if b.record(r.Err != nil) {
+					r.Err = ErrCircuitOpen
+				}
+				if !send(ctx, out, r) {
+					return
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Rate returns the fraction of the results in the current window that were errors, or
+// zero if there were none.
+func (b *RateBreaker[T]) Rate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(time.Now())
+	return b.rate()
+}
+
+// Open reports whether the breaker is currently rejecting results.
+func (b *RateBreaker[T]) Open() bool {
+	return b.Rate() > b.threshold
+}
+
+// record adds a result to the window and reports whether the breaker is open.
+func (b *RateBreaker[T]) record(failed bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.prune(now)
+	b.samples = append(b.samples, rateSample{at: now, failed: failed})
+	if failed {
+		b.failures++
+	}
+	return b.rate() > b.threshold
+}
+
+// prune drops the samples older than the window.
+func (b *RateBreaker[T]) prune(now time.Time) {
+	i := 0
+	for i < len(b.samples) && now.Sub(b.samples[i].at) > b.window {
+		if b.samples[i].failed {
+			b.failures--
+		}
+		i++
+	}
+	b.samples = b.samples[i:]
+}
+
+func (b *RateBreaker[T]) rate() float64 {
+	if len(b.samples) == 0 {
+		return 0
+	}
+	return float64(b.failures) / float64(len(b.samples))
+}
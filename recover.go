@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by a step wrapped with Recover when it panics. It carries the
+// recovered value, the stack of the panicking goroutine and the step it happened in, so
+// error handlers can tell panics from ordinary failures with
+// errors.As(err, &PanicError{}) and log the stack.
+type PanicError struct {
+	Value any    // the value passed to panic
+	Stack []byte // the stack trace at the point of recovery
+	Step  string // name of the step that panicked, empty if not known
+	Index int    // position of the step that panicked
+}
+
+func (e PanicError) Error() string {
+	if e.Step == "" {
+		return fmt.Sprintf("pipeline: panic: %v", e.Value)
+	}
+	return fmt.Sprintf("pipeline: panic in step %s (#%d): %v", e.Step, e.Index, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is sees through panics
+// raised with an error, and nil otherwise.
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover creates a MiddlewareFactory, for UseFactory, that turns a panic in a step into
+// a PanicError naming that step, returned with the step's input unchanged. Register it
+// first so it is outermost and also covers panics in the other middlewares.
+func Recover[T any]() MiddlewareFactory[T] {
+	return func(info StepInfo) Middleware[T] {
+		return func(next StepFunc[T]) StepFunc[T] {
+			return func(input T) (out T, err error) {
+				defer func() {
+					if v := recover(); v != nil {
+						out, err = input, PanicError{Value: v, Stack: debug.Stack(), Step: info.Name, Index: info.Index}
+					}
+				}()
+				return next(input)
+			}
+		}
+	}
+}
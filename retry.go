@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+)
+
+// Retry creates a Middleware that calls the wrapped step up to attempts times until it
+// succeeds. Before retry n (starting at 1) it sleeps for backoff(n); a nil backoff retries
+// immediately. The last error is returned if every attempt fails.
+func Retry[T any](attempts int, backoff func(int) time.Duration) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			out, err := next(input)
+			for n := 1; n < attempts && err != nil; n++ {
+				if backoff != nil {
+					time.Sleep(backoff(n))
+				}
+				out, err = next(input)
+			}
+			return out, err
+		}
+	}
+}
+
+// RetryUntil creates a Middleware that calls the wrapped step up to attempts times until
+// done reports its output as final, for polling a value that is not ready yet without
+// the step failing. It sleeps for backoff(n) before retry n like Retry. Retrying stops
+// at the first error, which is returned; if every attempt's output is not done, the
+// last output is returned with ErrMaxIterations.
+func RetryUntil[T any](attempts int, backoff func(int) time.Duration, done func(T) bool) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			for n := 0; ; n++ {
+				if n > 0 && backoff != nil {
+					time.Sleep(backoff(n))
+				}
+				out, err := next(input)
+				if err != nil || done(out) {
+					return out, err
+				}
+				if n+1 >= attempts {
+					return out, ErrMaxIterations
+				}
+			}
+		}
+	}
+}
+
+// RetryContext is the context-aware form of Retry. The backoff wait is interrupted when
+// ctx is done, in which case retrying stops immediately and ctx.Err() is returned.
+func RetryContext[T any](attempts int, backoff func(int) time.Duration) MiddlewareContext[T] {
+	return func(next StepFuncContext[T]) StepFuncContext[T] {
+		return func(ctx context.Context, input T) (T, error) {
+			out, err := next(ctx, input)
+			for n := 1; n < attempts && err != nil; n++ {
+				var wait time.Duration
+				if backoff != nil {
+					wait = backoff(n)
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return out, ctx.Err()
+				}
+				out, err = next(ctx, input)
+			}
+			return out, err
+		}
+	}
+}
+
+// ExecuteWithRetry runs the whole pipeline on input up to attempts times until an
+// execution succeeds, starting each one again from the original input, for idempotent
+// end-to-end operations where re-running every step is safe. It sleeps for backoff(n)
+// before retry n like Retry. The last error is returned if every attempt fails.
+func (p *Pipeline[T]) ExecuteWithRetry(input T, attempts int, backoff func(int) time.Duration) (T, error) {
+	return p.ExecuteWithRetryContext(context.Background(), input, attempts, backoff)
+}
+
+// ExecuteWithRetryContext is the context-aware form of ExecuteWithRetry. Once ctx is
+// done, no further attempt is made: the backoff wait is interrupted and ctx.Err() is
+// returned with the last attempt's output.
+func (p *Pipeline[T]) ExecuteWithRetryContext(ctx context.Context, input T, attempts int, backoff func(int) time.Duration) (T, error) {
+	out, err := p.ExecuteContext(ctx, input)
+	for n := 1; n < attempts && err != nil; n++ {
+		var wait time.Duration
+		if backoff != nil {
+			wait = backoff(n)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return out, ctx.Err()
+		}
+		out, err = p.ExecuteContext(ctx, input)
+	}
+	return out, err
+}
@@ -0,0 +1,36 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExecutionError is returned by the Execute methods of a pipeline created with
+// WithRichErrors when a step fails. It records where the failure happened and wraps the
+// step's error, so errors.Is still matches the underlying cause and
+// errors.As(err, &ExecutionError{}) recovers the details.
+type ExecutionError struct {
+	Step    string        // name of the failing step
+	Index   int           // position of the failing step
+	Input   any           // the value the failing step was called with
+	Elapsed time.Duration // time from the start of the execution to the failure
+	Err     error         // the step's error
+}
+
+func (e ExecutionError) Error() string {
+	return fmt.Sprintf("pipeline: step %s (#%d) failed after %v: %v", e.Step, e.Index, e.Elapsed, e.Err)
+}
+
+// Unwrap returns the step's error.
+func (e ExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// WithRichErrors makes the Execute methods wrap step failures in an ExecutionError.
+// Failures that happen between steps, such as cancellation or ErrBudgetExceeded, are
+// returned unchanged.
+func WithRichErrors[T any]() Option[T] {
+	return func(p *Pipeline[T]) {
+		p.richErrors = true
+	}
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+)
+
+// weightedRoutes holds routes with cumulative weights in name order.
+type weightedRoutes[T any] struct {
+	steps      []StepFunc[T]
+	cumulative []int
+	total      int
+}
+
+func newWeightedRoutes[T any](weights map[string]int, routes map[string]StepFunc[T]) weightedRoutes[T] {
+	names := make([]string, 0, len(weights))
+	for name, w := range weights {
+		if _, ok := routes[name]; ok && w > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var wr weightedRoutes[T]
+	for _, name := range names {
+		wr.total += weights[name]
+		wr.steps = append(wr.steps, routes[name])
+		wr.cumulative = append(wr.cumulative, wr.total)
+	}
+	return wr
+}
+
+// pick returns the route owning bucket n, for 0 <= n < total.
+func (wr weightedRoutes[T]) pick(n int) StepFunc[T] {
+	i := sort.SearchInts(wr.cumulative, n+1)
+	return wr.steps[i]
+}
+
+// WeightedRouter creates a StepFunc that sends each input to a randomly chosen route,
+// with probability proportional to the route's weight, e.g. for A/B experiments. Only
+// names present in both maps with a positive weight are eligible; with none, the step
+// returns ErrNoRoute. It is safe for concurrent use. It always draws from the global
+// generator, even in a pipeline created WithRandSource; use WeightedRouterContext for
+// reproducible routing.
+func WeightedRouter[T any](weights map[string]int, routes map[string]StepFunc[T]) StepFunc[T] {
+	wr := newWeightedRoutes(weights, routes)
+	return func(input T) (T, error) {
+		if wr.total == 0 {
+			return input, ErrNoRoute
+		}
+		return wr.pick(rand.IntN(wr.total))(input)
+	}
+}
+
+// WeightedRouterContext is the context-aware form of WeightedRouter. When run by a
+// pipeline created WithRandSource, it draws from that source, so the routes taken are
+// reproducible.
+func WeightedRouterContext[T any](weights map[string]int, routes map[string]StepFunc[T]) StepFuncContext[T] {
+	wr := newWeightedRoutes(weights, routes)
+	return func(ctx context.Context, input T) (T, error) {
+		if wr.total == 0 {
+			return input, ErrNoRoute
+		}
+		return wr.pick(randIntN(ctx, wr.total))(input)
+	}
+}
+
+// StickyWeightedRouter is like WeightedRouter but buckets inputs by key instead of at
+// random, so inputs with the same key always take the same route for a given set of
+// weights.
+func StickyWeightedRouter[T any](weights map[string]int, routes map[string]StepFunc[T], key func(T) string) StepFunc[T] {
+	wr := newWeightedRoutes(weights, routes)
+	return func(input T) (T, error) {
+		if wr.total == 0 {
+			return input, ErrNoRoute
+		}
+		h := fnv.New64a()
+		h.Write([]byte(key(input)))
+		return wr.pick(int(h.Sum64() % uint64(wr.total)))(input)
+	}
+}
@@ -0,0 +1,45 @@
+package pipeline
+
+import "sync"
+
+// errorStats tallies step failures by step name. A nil *errorStats records nothing.
+type errorStats struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (s *errorStats) record(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.counts[name]++
+	s.mu.Unlock()
+}
+
+func (s *errorStats) snapshot() map[string]int {
+	out := make(map[string]int)
+	if s == nil {
+		return out
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, n := range s.counts {
+		out[name] = n
+	}
+	return out
+}
+
+// WithErrorStats enables per-step failure counting, reported by ErrorStats.
+func WithErrorStats[T any]() Option[T] {
+	return func(p *Pipeline[T]) {
+		p.errorStats = &errorStats{counts: make(map[string]int)}
+	}
+}
+
+// ErrorStats returns a snapshot of how often each step has failed, keyed by step name.
+// It is safe to call while the pipeline is executing. The map is empty unless the
+// pipeline was created with WithErrorStats.
+func (p *Pipeline[T]) ErrorStats() map[string]int {
+	return p.errorStats.snapshot()
+}
@@ -0,0 +1,304 @@
+package pipeline
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Result pairs a value with the error produced while computing it.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// StreamStage transforms a stream of results. A stage must close its output channel
+// once in is closed or ctx is done. Stages forward input errors downstream unchanged
+// unless documented otherwise.
+type StreamStage[T any] func(ctx context.Context, in <-chan Result[T]) <-chan Result[T]
+
+// ToResults adapts a channel of plain values into a stream of successful Results that
+// StreamStages can consume. The output is closed once in is closed or ctx is done.
+func ToResults[T any](ctx context.Context, in <-chan T) <-chan Result[T] {
+	out := make(chan Result[T])
+	go func() {
+		defer close(out)
+		for v, ok := receive(ctx, in); ok; v, ok = receive(ctx, in) {
+			if !send(ctx, out, Result[T]{Value: v}) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// send delivers v on out unless ctx is done first, reporting whether it was sent.
+func send[V any](ctx context.Context, out chan<- V, v V) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receive takes the next value from in, reporting false once in is closed or ctx is
+// done.
+func receive[V any](ctx context.Context, in <-chan V) (V, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-ctx.Done():
+		var zero V
+		return zero, false
+	}
+}
+
+// WindowParallel creates a StreamStage that groups successful values into windows of
+// windowSize and, for each window, runs every step on every value concurrently. The
+// outputs are passed to combiner, ordered by value and then by step, and the combined
+// value is emitted as one Result per window, so a window can be rolled up into a single
+// value. A trailing partial window is processed when the input closes. If any step
+// fails, the window's Result carries the first error by position instead.
+func WindowParallel[T any](windowSize int, steps []StepFunc[T], combiner func([]T) (T, error)) StreamStage[T] {
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	return func(ctx context.Context, in <-chan Result[T]) <-chan Result[T] {
+		out := make(chan Result[T])
+		go func() {
+			defer close(out)
+			window := make([]T, 0, windowSize)
+			flush := func() bool {
+				if len(window) == 0 {
+					return true
+				}
+				v, err := scatterGather(window, steps, combiner)
+				window = window[:0]
+				return send(ctx, out, Result[T]{Value: v, Err: err})
+			}
+			for r, ok := receive(ctx, in); ok; r, ok = receive(ctx, in) {
+				if r.Err != nil {
+					if !send(ctx, out, r) {
+						return
+					}
+					continue
+				}
+				window = append(window, r.Value)
+				if len(window) == windowSize && !flush() {
+					return
+				}
+			}
+			if ctx.Err() == nil {
+				flush()
+			}
+		}()
+		return out
+	}
+}
+
+// scatterGather runs every step on every value concurrently and combines the outputs.
+func scatterGather[T any](values []T, steps []StepFunc[T], combiner func([]T) (T, error)) (T, error) {
+	n := len(values) * len(steps)
+	results := make([]T, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i, v := range values {
+		for j, s := range steps {
+			go func(idx int, s StepFunc[T], v T) {
+				defer wg.Done()
+				results[idx], errs[idx] = s(v)
+			}(i*len(steps)+j, s, v)
+		}
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	return combiner(results)
+}
+
+// Coalesce creates a StreamStage that merges consecutive successful values. The first
+// value is held; each following value is passed to merge with the held one, and merge
+// returns the merged value and whether to keep holding it. When it reports false, the
+// merged value is emitted and the next value starts a new run. This accumulates partial
+// updates until a commit marker, and generalizes debouncing or deduplication. An error
+// result emits the held value first and is then forwarded; a held value is also emitted
+// when the input closes.
+func Coalesce[T any](merge func(prev, curr T) (T, bool)) StreamStage[T] {
+	return func(ctx context.Context, in <-chan Result[T]) <-chan Result[T] {
+		out := make(chan Result[T])
+		go func() {
+			defer close(out)
+			var (
+				held    T
+				holding bool
+			)
+			flush := func() bool {
+				if !holding {
+					return true
+				}
+				holding = false
+				return send(ctx, out, Result[T]{Value: held})
+			}
+			for r, ok := receive(ctx, in); ok; r, ok = receive(ctx, in) {
+				if r.Err != nil {
+					if !flush() || !send(ctx, out, r) {
+						return
+					}
+					continue
+				}
+				if !holding {
+					held, holding = r.Value, true
+					continue
+				}
+				merged, hold := merge(held, r.Value)
+				if hold {
+					held = merged
+					continue
+				}
+				holding = false
+				if !send(ctx, out, Result[T]{Value: merged}) {
+					return
+				}
+			}
+			if ctx.Err() == nil {
+				flush()
+			}
+		}()
+		return out
+	}
+}
+
+// Fold consumes in until it is closed, folding each value into an accumulator that
+// starts at initial, and returns the result without buffering the stream. If ctx is
+// done first, the accumulator so far is returned with ctx.Err().
+func Fold[T, A any](ctx context.Context, in <-chan T, initial A, f func(A, T) A) (A, error) {
+	acc := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return acc, ctx.Err()
+		case v, ok := <-in:
+			if !ok {
+				return acc, nil
+			}
+			acc = f(acc, v)
+		}
+	}
+}
+
+// MapReduceStream creates a stream stage that groups successful values into chunks of
+// chunkSize, applies mapFn to the values of each chunk on up to workers goroutines,
+// and emits reduceFn of the mapped values as one Result per chunk, in chunk order. A
+// trailing partial chunk is processed when the input closes. An input error is
+// forwarded after the values buffered before it have been emitted as a chunk.
+func MapReduceStream[T, R any](chunkSize, workers int, mapFn func(T) R, reduceFn func([]R) R) func(ctx context.Context, in <-chan Result[T]) <-chan Result[R] {
+	chunkSize, workers = max(1, chunkSize), max(1, workers)
+	return func(ctx context.Context, in <-chan Result[T]) <-chan Result[R] {
+		out := make(chan Result[R])
+		go func() {
+			defer close(out)
+			chunk := make([]T, 0, chunkSize)
+			flush := func() bool {
+				if len(chunk) == 0 {
+					return true
+				}
+				r := reduceFn(mapChunk(chunk, workers, mapFn))
+				chunk = chunk[:0]
+				return send(ctx, out, Result[R]{Value: r})
+			}
+			for r, ok := receive(ctx, in); ok; r, ok = receive(ctx, in) {
+				if r.Err != nil {
+					if !flush() || !send(ctx, out, Result[R]{Err: r.Err}) {
+						return
+					}
+					continue
+				}
+				chunk = append(chunk, r.Value)
+				if len(chunk) == chunkSize && !flush() {
+					return
+				}
+			}
+			if ctx.Err() == nil {
+				flush()
+			}
+		}()
+		return out
+	}
+}
+
+// mapChunk applies mapFn to every value on up to workers goroutines, keeping order.
+func mapChunk[T, R any](values []T, workers int, mapFn func(T) R) []R {
+	mapped := make([]R, len(values))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(min(workers, len(values)))
+	for range min(workers, len(values)) {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				mapped[i] = mapFn(values[i])
+			}
+		}()
+	}
+	for i := range values {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return mapped
+}
+
+// Partition splits in into one output channel per route, sending each value to the
+// first route, in name order, whose predicate matches it. Values matching no route go
+// to the channel under the empty name, or are dropped if dropUnmatched is set, in which
+// case there is no such channel. The empty name is reserved for those values, so
+// Partition panics if a route uses it. Every output is closed once in is closed or ctx
+// is done. Outputs are unbuffered and fed by one goroutine, so each must be drained for
+// the others to make progress.
+func Partition[T any](ctx context.Context, in <-chan T, routes map[string]func(T) bool, dropUnmatched bool) map[string]<-chan T {
+	if _, ok := routes[""]; ok {
+		panic("pipeline: Partition route name must not be empty")
+	}
+	names := slices.Sorted(maps.Keys(routes))
+	outs := make(map[string]chan T, len(routes)+1)
+	result := make(map[string]<-chan T, len(routes)+1)
+	for _, name := range names {
+		outs[name] = make(chan T)
+		result[name] = outs[name]
+	}
+	var unmatched chan T
+	if !dropUnmatched {
+		unmatched = make(chan T)
+		result[""] = unmatched
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+			if unmatched != nil {
+				close(unmatched)
+			}
+		}()
+		for v, ok := receive(ctx, in); ok; v, ok = receive(ctx, in) {
+			target := unmatched
+			for _, name := range names {
+				if routes[name](v) {
+					target = outs[name]
+					break
+				}
+			}
+			if target != nil && !send(ctx, target, v) {
+				return
+			}
+		}
+	}()
+	return result
+}
@@ -0,0 +1,29 @@
+package pipeline
+
+import "time"
+
+// StepTiming is how long one step of an execution took.
+type StepTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// ExecutionSummary describes one finished execution, for logging a single structured
+// line per request instead of per-step events.
+type ExecutionSummary struct {
+	ExecutionID string        // the execution id, as returned by ExecutionID
+	Duration    time.Duration // total time spent in the execution, pre- and post-steps included
+	Steps       []StepTiming  // the steps that ran, in the order they ran, failing step included
+	FailedStep  string        // name of the step that failed, or empty
+	Err         error         // the error the execution returned, or nil
+}
+
+// WithSummary registers fn to be called with an ExecutionSummary once per execution, as
+// it returns and before any finalizers run. Executions get an execution id as they do
+// with WithEventSink. Errors raised between steps, such as cancellation, are reported in
+// Err with an empty FailedStep.
+func WithSummary[T any](fn func(ExecutionSummary)) Option[T] {
+	return func(p *Pipeline[T]) {
+		p.summary = fn
+	}
+}
@@ -0,0 +1,29 @@
+package pipeline
+
+import "maps"
+
+// ThenTagged appends a StepFunc labelled with tags, applying any registered Middleware.
+// Tags such as team, pii or cost-tier let steps be found at runtime with StepsByTag.
+// The map is copied, so later changes to it do not affect the step.
+func (p *Pipeline[T]) ThenTagged(tags map[string]string, step StepFunc[T]) *Pipeline[T] {
+	return p.add(stage[T]{tags: maps.Clone(tags)}, step, nil)
+}
+
+// UseForTag appends a Middleware that applies only to steps tagged key=value with
+// ThenTagged, such as audit logging for steps tagged pii=true. Like every middleware it
+// is resolved when the pipeline is compiled, so it also covers matching steps added
+// afterwards.
+func (p *Pipeline[T]) UseForTag(key, value string, mw Middleware[T]) *Pipeline[T] {
+	return p.addMiddleware(middleware[T]{plain: mw, tagKey: key, tagValue: value})
+}
+
+// StepsByTag returns the indices, in execution order, of the steps tagged key=value.
+func (p *Pipeline[T]) StepsByTag(key, value string) []int {
+	var indices []int
+	for i, s := range p.stages() {
+		if v, ok := s.tags[key]; ok && v == value {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
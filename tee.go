@@ -0,0 +1,29 @@
+package pipeline
+
+import "sync/atomic"
+
+// Tee creates a StepFunc that sends the current value to side and passes it through
+// unchanged, for feeding an audit or analytics stream off the main path. The send
+// blocks until side has room, so a stalled reader stalls the pipeline; use
+// TeeNonBlocking when the side stream must never hold up processing.
+func Tee[T any](side chan<- T) StepFunc[T] {
+	return func(input T) (T, error) {
+		side <- input
+		return input, nil
+	}
+}
+
+// TeeNonBlocking is like Tee but drops the value instead of waiting when side is full,
+// counting each dropped value in dropped if it is not nil.
+func TeeNonBlocking[T any](side chan<- T, dropped *atomic.Uint64) StepFunc[T] {
+	return func(input T) (T, error) {
+		select {
+		case side <- input:
+		default:
+			if dropped != nil {
+				dropped.Add(1)
+			}
+		}
+		return input, nil
+	}
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Timeout creates a Middleware that fails a step with ErrStepTimeout if it has not
+// returned within d. A plain StepFunc cannot be interrupted, so it keeps running in the
+// background and its eventual result is discarded; prefer TimeoutContext for steps
+// that can observe cancellation.
+func Timeout[T any](d time.Duration) Middleware[T] {
+	return func(next StepFunc[T]) StepFunc[T] {
+		return func(input T) (T, error) {
+			type result struct {
+				out T
+				err error
+			}
+			done := make(chan result, 1)
+			go func() {
+				out, err := next(input)
+				done <- result{out, err}
+			}()
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case r := <-done:
+				return r.out, r.err
+			case <-timer.C:
+				return input, ErrStepTimeout
+			}
+		}
+	}
+}
+
+// TimeoutContext creates a MiddlewareContext that runs each step with a context that
+// expires after d. If the step fails because that deadline passed, ErrStepTimeout is
+// returned; cancellation of the caller's own context is reported unchanged.
+func TimeoutContext[T any](d time.Duration) MiddlewareContext[T] {
+	return func(next StepFuncContext[T]) StepFuncContext[T] {
+		return func(ctx context.Context, input T) (T, error) {
+			stepCtx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			out, err := next(stepCtx, input)
+			if err != nil && ctx.Err() == nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+				return out, ErrStepTimeout
+			}
+			return out, err
+		}
+	}
+}
+
+// ExecuteWithTimeout runs the pipeline like ExecuteContext under a deadline of d and, if
+// the deadline passes first, returns onTimeout applied to the original input instead,
+// for serving a cached or approximate answer when the full pipeline is too slow. It
+// returns as soon as the deadline passes; the abandoned execution's context is
+// cancelled, so it stops at its next step boundary or context-aware step. A nil
+// onTimeout returns the input with ErrDeadlineExceeded. Cancellation of ctx itself is
+// reported unchanged, without calling onTimeout.
+func (p *Pipeline[T]) ExecuteWithTimeout(ctx context.Context, d time.Duration, input T, onTimeout func(T) (T, error)) (T, error) {
+	runCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	var r Result[T]
+	select {
+	case r = <-p.ExecuteChan(runCtx, input):
+		if r.Err == nil || ctx.Err() != nil || runCtx.Err() == nil {
+			return r.Value, r.Err
+		}
+	case <-runCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return input, err
+		}
+	}
+	if onTimeout == nil {
+		return input, ErrDeadlineExceeded
+	}
+	return onTimeout(input)
+}
+
+// ThenDeadline appends a StepFunc under the given name that fails with ErrStepTimeout
+// if a call takes longer than d, applying any registered Middleware around it, so the
+// deadline applies to each attempt of a retried step. Timeouts count as failures of
+// the named step in ErrorStats. A plain step cannot be interrupted and keeps running in
+// the background, as with Timeout; use ThenDeadlineContext to cancel the step itself.
+func (p *Pipeline[T]) ThenDeadline(name string, d time.Duration, step StepFunc[T]) *Pipeline[T] {
+	return p.add(stage[T]{name: name}, Timeout[T](d)(step), nil)
+}
+
+// ThenDeadlineContext is the context-aware form of ThenDeadline: the step's context
+// expires after d, as with TimeoutContext.
+func (p *Pipeline[T]) ThenDeadlineContext(name string, d time.Duration, step StepFuncContext[T]) *Pipeline[T] {
+	return p.add(stage[T]{name: name}, nil, TimeoutContext[T](d)(step))
+}
@@ -0,0 +1,38 @@
+package pipeline
+
+import "context"
+
+// Worker reads inputs from in, runs each through the pipeline with ExecuteContext and
+// writes a Result to out, until in is closed or ctx is done. It does not close out, so
+// several workers can share the same channels; the pipeline itself is safe for
+// concurrent use, which makes Worker a building block for consumer pools.
+func (p *Pipeline[T]) Worker(ctx context.Context, in <-chan T, out chan<- Result[T]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case input, ok := <-in:
+			if !ok {
+				return
+			}
+			v, err := p.ExecuteContext(ctx, input)
+			if !send(ctx, out, Result[T]{Value: v, Err: err}) {
+				return
+			}
+		}
+	}
+}
+
+// ExecuteChan runs the pipeline in a new goroutine and returns a channel that receives
+// its Result and is then closed, so callers can select on completion alongside other
+// events. The channel is buffered, so the goroutine never blocks on an abandoned
+// result; cancelling ctx stops the execution as ExecuteContext does.
+func (p *Pipeline[T]) ExecuteChan(ctx context.Context, input T) <-chan Result[T] {
+	out := make(chan Result[T], 1)
+	go func() {
+		defer close(out)
+		v, err := p.ExecuteContext(ctx, input)
+		out <- Result[T]{Value: v, Err: err}
+	}()
+	return out
+}
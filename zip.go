@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Pair holds one value of each of two types, for pipelines that process two related
+// records together.
+type Pair[A, B any] struct {
+	A A
+	B B
+}
+
+// Zip creates a context-aware step that runs pa on the pair's A and pb on its B
+// concurrently and pairs their outputs. If either pipeline fails, the context passed to
+// the other is cancelled so its context-aware steps can stop early, and the input is
+// returned unchanged with the first error.
+func Zip[A, B any](pa *Pipeline[A], pb *Pipeline[B]) StepFuncContext[Pair[A, B]] {
+	return func(ctx context.Context, input Pair[A, B]) (Pair[A, B], error) {
+		branchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var (
+			wg       sync.WaitGroup
+			once     sync.Once
+			firstErr error
+			out      Pair[A, B]
+		)
+		fail := func(err error) {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var err error
+			if out.A, err = pa.ExecuteContext(branchCtx, input.A); err != nil {
+				fail(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			var err error
+			if out.B, err = pb.ExecuteContext(branchCtx, input.B); err != nil {
+				fail(err)
+			}
+		}()
+		wg.Wait()
+		if firstErr != nil {
+			return input, firstErr
+		}
+		return out, nil
+	}
+}